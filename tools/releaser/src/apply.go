@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"releaser/tool/githubapi"
+	"releaser/tool/gitops"
+	"releaser/tool/output"
+	"releaser/tool/plan"
+	"releaser/tool/release"
+	"releaser/tool/shared"
+)
+
+// planPath returns the plan file given on the command line, or the default
+// one inside the .git directory so it never dirties the working tree.
+func planPath(cfg *shared.Config) (string, error) {
+	if cfg.PlanFile != "" {
+		return cfg.PlanFile, nil
+	}
+	gitDir, err := gitops.GitDir(cfg.BaseDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "releaser", "plan.json"), nil
+}
+
+// writePlan saves the releases recorded by `releaser plan` for review,
+// signed off by the git identity of whoever made the plan.
+func writePlan(cfg *shared.Config, released []*shared.Config) error {
+	if len(released) == 0 {
+		output.Info("Nothing to plan.")
+		return nil
+	}
+	head, err := gitops.Head(cfg.BaseDir)
+	if err != nil {
+		return err
+	}
+	signer, err := gitops.Identity(cfg.BaseDir)
+	if err != nil {
+		return err
+	}
+	path, err := planPath(cfg)
+	if err != nil {
+		return err
+	}
+
+	var releases []*plan.Plan
+	for _, rel := range released {
+		p := release.Describe(rel)
+		output.Blank()
+		p.Print()
+		releases = append(releases, p)
+	}
+	if err := plan.NewFile(cfg.Repo, head, signer, releases).Write(path, []byte(os.Getenv(plan.KeyEnv))); err != nil {
+		return err
+	}
+	output.Success("Release plan written to " + path + "; publish it with: releaser apply " + path)
+	return nil
+}
+
+// applyPlan publishes the releases of a reviewed plan, provided the
+// repository is still at the commit the plan was made on.
+func applyPlan(cfg *shared.Config) ([]*shared.Config, error) {
+	path, err := planPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+	f, err := plan.ReadFile(path, []byte(os.Getenv(plan.KeyEnv)))
+	if err != nil {
+		return nil, err
+	}
+	output.Info("Applying release plan " + path + " signed off by " + f.SignedBy + " at " + f.CreatedAt)
+	if err := preflight(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Repo != f.Repo {
+		return nil, fmt.Errorf("the plan was made for %s, not %s", f.Repo, cfg.Repo)
+	}
+	head, err := gitops.Head(cfg.BaseDir)
+	if err != nil {
+		return nil, err
+	}
+	if head != f.BaseSHA {
+		return nil, fmt.Errorf("HEAD is at %s but the plan was made on %s; run releaser plan again", head, f.BaseSHA)
+	}
+
+	var released []*shared.Config
+	for _, p := range f.Releases {
+		c, err := planConfig(cfg, p)
+		if err != nil {
+			return released, err
+		}
+		output.Blank()
+		output.Info(fmt.Sprintf("Applying %s release %s (was %s)", p.Type, p.NewTag, p.OldTag))
+		if err := applyRelease(c, p); err != nil {
+			if p.Component != "" {
+				return released, fmt.Errorf("component %s: %w", p.Component, err)
+			}
+			return released, err
+		}
+		released = append(released, c)
+	}
+	return released, nil
+}
+
+// planConfig rebuilds the release configuration recorded in p.
+func planConfig(cfg *shared.Config, p *plan.Plan) (*shared.Config, error) {
+	c := cfg
+	if p.Component != "" {
+		component, ok := findComponent(cfg.Project.Components, p.Component)
+		if !ok {
+			return nil, fmt.Errorf("unknown component in plan: %s", p.Component)
+		}
+		c = cfg.ForComponent(component)
+	}
+	c.Type = p.Type
+	c.OldTag = p.OldTag
+	c.OldVer = p.OldVersion
+	c.NewTag = p.NewTag
+	c.NewVer = p.NewVersion
+	c.Prerelease = p.Prerelease
+	c.Changes = p.Notes
+	return c, nil
+}
+
+// applyRelease writes the planned files, then commits, tags and publishes
+// the release.
+func applyRelease(cfg *shared.Config, p *plan.Plan) error {
+	for _, action := range p.Actions {
+		if action.Kind != plan.KindWrite {
+			continue
+		}
+		output.Info("Updating " + action.Path + "...")
+		if err := cfg.Plan.WriteFile(cfg.BaseDir, action.Path, []byte(action.Content)); err != nil {
+			return err
+		}
+		cfg.ReleaseFiles = append(cfg.ReleaseFiles, action.Path)
+	}
+	for _, step := range []struct {
+		name string
+		fn   func(*shared.Config) error
+	}{
+		{name: "CommitRelease", fn: release.CommitRelease},
+		{name: "CreateTag", fn: release.CreateTag},
+		{name: "CreateRelease", fn: githubapi.CreateRelease},
+	} {
+		output.Verbose("Running release step: " + step.name)
+		if err := step.fn(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findComponent(components []shared.Component, name string) (shared.Component, bool) {
+	for _, component := range components {
+		if component.Name == name {
+			return component, true
+		}
+	}
+	return shared.Component{}, false
+}
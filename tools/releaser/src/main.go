@@ -1,28 +1,44 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
 	"releaser/tool/cli"
 	"releaser/tool/env"
 	"releaser/tool/githubapi"
 	"releaser/tool/gitops"
+	"releaser/tool/journal"
+	"releaser/tool/monorepo"
 	"releaser/tool/output"
+	"releaser/tool/plan"
 	"releaser/tool/release"
 	"releaser/tool/releasetype"
+	"releaser/tool/settings"
 	"releaser/tool/shared"
 	"releaser/tool/version"
 )
 
 func main() {
 	if err := run(os.Args); err != nil {
-		output.Error(err.Error())
+		output.Error(withPromptHint(err).Error())
 		output.Exit(1)
 	}
 }
 
+// withPromptHint tells how to answer a decision that could not be prompted
+// for in non-interactive mode.
+func withPromptHint(err error) error {
+	var missing *output.MissingDecisionError
+	if !errors.As(err, &missing) {
+		return err
+	}
+	return fmt.Errorf("%w; pass it on the command line or set prompts.%s in %s", err, missing.Decision, settings.FileName)
+}
+
 func run(args []string) error {
 	cfg := &shared.Config{
 		Follow: true,
@@ -38,18 +54,56 @@ func run(args []string) error {
 		output.Verbose("Verbose logging enabled")
 	}
 	output.Verbose("CLI args parsed successfully")
+	if cfg.Command == "plan" {
+		cfg.DryRun = true
+	}
+	if cfg.DryRun {
+		cfg.Plan = &plan.Plan{}
+		output.Info("Dry run: nothing will be written, tagged, pushed or published")
+	}
 	if err := prepareEnvironment(cfg); err != nil {
 		return err
 	}
-	if err := runReleaseFlow(cfg); err != nil {
+	if (cfg.Command == "plan" || cfg.Command == "apply") && os.Getenv(plan.KeyEnv) == "" {
+		output.Warn(plan.KeyEnv + " is not set")
+		return fmt.Errorf("%s is required to sign and verify release plans", plan.KeyEnv)
+	}
+	if cfg.NonInteractive || env.IsCI() || !output.StdinIsTerminal() {
+		cfg.NonInteractive = true
+		output.SetPrompter(output.NonInteractive(cfg.Project.Prompts))
+		output.Verbose("Non-interactive mode: prompts are answered from the prompts settings of " + settings.FileName)
+	}
+	if cfg.Command == "rollback" {
+		return rollback(cfg)
+	}
+	var released []*shared.Config
+	var err error
+	if cfg.Command == "apply" {
+		released, err = applyPlan(cfg)
+	} else {
+		released, err = runReleaseFlow(cfg)
+	}
+	if err != nil {
 		return err
 	}
 
-	output.Success("Created GitHub release: " + cfg.Release)
+	if cfg.Command == "plan" {
+		return writePlan(cfg, released)
+	}
+	if cfg.DryRun {
+		for _, rel := range released {
+			output.Blank()
+			release.Describe(rel).Print()
+		}
+		return nil
+	}
 
-	if cfg.Follow {
-		if err := githubapi.FollowReleaseWorkflow(cfg); err != nil {
-			output.Warn("Follow mode failed: " + err.Error())
+	for _, rel := range released {
+		output.Success("Created GitHub release: " + rel.Release)
+		if cfg.Follow {
+			if err := githubapi.FollowReleaseWorkflow(rel); err != nil {
+				output.Warn("Follow mode failed: " + err.Error())
+			}
 		}
 	}
 
@@ -79,6 +133,10 @@ func prepareEnvironment(cfg *shared.Config) error {
 		cfg.BaseDir = env.DetectBaseDir()
 	}
 	output.Verbose("Base directory resolved to: " + cfg.BaseDir)
+	if err := settings.Load(cfg); err != nil {
+		output.Warn(err.Error())
+		return err
+	}
 
 	cfg.Token = os.Getenv("GITHUB_TOKEN")
 	if cfg.Token == "" {
@@ -99,8 +157,35 @@ func prepareEnvironment(cfg *shared.Config) error {
 	return nil
 }
 
-func runReleaseFlow(cfg *shared.Config) error {
+func runReleaseFlow(cfg *shared.Config) ([]*shared.Config, error) {
 	output.Verbose("Starting release flow")
+	if err := preflight(cfg); err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Project.Components) > 0 {
+		return releaseComponents(cfg)
+	}
+	if len(cfg.Components) > 0 || cfg.AllComponents {
+		return nil, fmt.Errorf("--component and --all need components defined in %s", settings.FileName)
+	}
+	if cfg.TypeSet {
+		if err := version.CheckType(cfg); err != nil {
+			return nil, err
+		}
+	}
+	output.Verbose("Running preflight step: GetCurrentVersion")
+	if err := version.GetCurrentVersion(cfg); err != nil {
+		return nil, err
+	}
+	if err := releaseSteps(cfg); err != nil {
+		return nil, err
+	}
+	return []*shared.Config{cfg}, nil
+}
+
+// preflight checks the working tree and resolves the GitHub repository.
+func preflight(cfg *shared.Config) error {
 	if !gitops.IsGitRepo(cfg.BaseDir) {
 		output.Warn(fmt.Sprintf("'%s' is not a git working tree, yon can set RELEASER_BASE_DIR your .env file", cfg.BaseDir))
 		return fmt.Errorf("not a git repository: %s", cfg.BaseDir)
@@ -109,6 +194,15 @@ func runReleaseFlow(cfg *shared.Config) error {
 		output.Warn("Required command 'git' not found in PATH")
 		return err
 	}
+	if !cfg.DryRun && (cfg.Command == "" || cfg.Command == "rollback") {
+		gitDir, err := gitops.GitDir(cfg.BaseDir)
+		if err != nil {
+			return err
+		}
+		if cfg.Journal, err = journal.Load(gitDir); err != nil {
+			return err
+		}
+	}
 
 	for _, step := range []struct {
 		name string
@@ -116,17 +210,141 @@ func runReleaseFlow(cfg *shared.Config) error {
 	}{
 		{name: "CheckUncommittedChanges", fn: gitops.CheckUncommittedChanges},
 		{name: "GetRepository", fn: gitops.GetRepository},
-		{name: "GetCurrentVersion", fn: githubapi.GetCurrentVersion},
 	} {
 		output.Verbose("Running preflight step: " + step.name)
 		if err := step.fn(cfg); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// releaseComponents releases the components selected with --component, or
+// every component changed since its last tag, along with the components
+// depending on them, dependencies first.
+func releaseComponents(cfg *shared.Config) ([]*shared.Config, error) {
+	components, explicit, err := selectComponents(cfg)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := monorepo.Load(cfg.BaseDir, cfg.Project.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := make(map[string]*shared.Config)
+	var names []string
+	for _, component := range components {
+		output.Blank()
+		output.Info("Component " + component.Name + " (" + component.Path + ")")
+		c := cfg.ForComponent(component)
+		if err := version.GetCurrentVersion(c); err != nil {
+			if explicit {
+				return nil, fmt.Errorf("component %s: %w", component.Name, err)
+			}
+			output.Warn("Skipping component " + component.Name + ": " + err.Error())
+			continue
+		}
+		if !explicit && cfg.Journal.Find(component.Name) == nil {
+			changed, err := gitops.HasChanges(c.BaseDir, c.OldTag, component.Path)
+			if err != nil {
+				return nil, err
+			}
+			if !changed {
+				output.Info("No changes in " + component.Name + " since " + c.OldTag + "; skipping.")
+				continue
+			}
+		}
+		scoped[component.Name] = c
+		names = append(names, component.Name)
+	}
 
+	for _, name := range graph.WithDependents(names) {
+		if _, ok := scoped[name]; ok {
+			continue
+		}
+		component, _ := graph.Component(name)
+		output.Blank()
+		output.Info("Component " + name + " (" + component.Path + ") depends on " + strings.Join(graph.Requires(name), ", ") + "; proposing a release")
+		c := cfg.ForComponent(component)
+		if err := version.GetCurrentVersion(c); err != nil {
+			return nil, fmt.Errorf("component %s: %w", name, err)
+		}
+		scoped[name] = c
+		names = append(names, name)
+	}
+
+	order, err := graph.Order(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(order) > 1 {
+		output.Info("Release order: " + strings.Join(order, " → "))
+	}
 	if cfg.TypeSet {
+		for _, name := range order {
+			if err := version.CheckType(scoped[name]); err != nil {
+				return nil, fmt.Errorf("component %s: %w", name, err)
+			}
+		}
+	}
+
+	bumps := make(map[string]string)
+	var released []*shared.Config
+	for _, name := range order {
+		c := scoped[name]
+		output.Blank()
+		output.Info("Releasing component " + name + " from " + c.OldTag)
+		c.DependencyBumps = make(map[string]string)
+		for _, dep := range graph.Requires(name) {
+			for _, pkg := range graph.Packages(dep) {
+				if v, ok := bumps[pkg]; ok {
+					c.DependencyBumps[pkg] = v
+				}
+			}
+		}
+		err := releaseSteps(c)
+		if errors.Is(err, release.ErrAborted) && len(order) > 1 {
+			output.Info("Skipping component " + name + ".")
+			continue
+		}
+		if err != nil {
+			return released, fmt.Errorf("component %s: %w", name, err)
+		}
+		released = append(released, c)
+		for _, pkg := range graph.Packages(name) {
+			bumps[pkg] = c.NewVer
+		}
+	}
+	if len(released) == 0 {
+		output.Info("No component to release.")
+	}
+	return released, nil
+}
+
+func selectComponents(cfg *shared.Config) ([]shared.Component, bool, error) {
+	if len(cfg.Components) == 0 {
+		return cfg.Project.Components, false, nil
+	}
+	var selected []shared.Component
+	for _, name := range cfg.Components {
+		component, ok := findComponent(cfg.Project.Components, name)
+		if !ok {
+			return nil, false, fmt.Errorf("unknown component: %s", name)
+		}
+		selected = append(selected, component)
+	}
+	return selected, true, nil
+}
+
+func releaseSteps(cfg *shared.Config) error {
+	entry := resumeRelease(cfg)
+	switch {
+	case entry.LastStep() != "":
+		output.Info("Resuming release " + entry.NewTag + " after step " + entry.LastStep())
+	case cfg.TypeSet:
 		output.Info("Skipping auto-detect; using provided release type: " + cfg.Type)
-	} else {
+	default:
 		if err := releasetype.Detect(cfg); err != nil {
 			return err
 		}
@@ -137,15 +355,28 @@ func runReleaseFlow(cfg *shared.Config) error {
 		fn   func(*shared.Config) error
 	}{
 		{name: "VersionBump", fn: version.Bump},
-		{name: "CreateTag", fn: release.CreateTag},
+		{name: "Confirm", fn: release.Confirm},
+		{name: "UpdateVersionFiles", fn: version.UpdateFiles},
+		{name: "UpdateConstraints", fn: monorepo.UpdateConstraints},
 		{name: "BuildChanges", fn: release.BuildChanges},
+		{name: "CommitRelease", fn: release.CommitRelease},
+		{name: "CreateTag", fn: release.CreateTag},
 		{name: "CreateRelease", fn: githubapi.CreateRelease},
 	} {
+		if entry.Done(step.name) {
+			output.Verbose("Skipping completed release step: " + step.name)
+			continue
+		}
 		output.Verbose("Running release step: " + step.name)
 		if err := step.fn(cfg); err != nil {
+			if errors.Is(err, release.ErrAborted) {
+				forgetRelease(cfg)
+			}
 			return err
 		}
+		recordStep(cfg, entry, step.name)
 	}
 
+	forgetRelease(cfg)
 	return nil
 }
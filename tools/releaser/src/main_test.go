@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"releaser/tool/output"
+	"releaser/tool/shared"
+)
+
+var testComponents = []shared.Component{
+	{Name: "api", Path: "packages/api", TagPrefix: "api-v"},
+	{Name: "api-client", Path: "packages/api-client", TagPrefix: "api-client-v"},
+	{Name: "web", Path: "apps/web", TagPrefix: "web@"},
+}
+
+func TestSelectComponents(t *testing.T) {
+	cfg := &shared.Config{Project: shared.Project{Components: testComponents}}
+	selected, explicit, err := selectComponents(cfg)
+	if err != nil || explicit || len(selected) != 3 {
+		t.Fatalf("expected every component without --component, got %v, %v, %v", selected, explicit, err)
+	}
+
+	cfg.Components = []string{"web", "api"}
+	selected, explicit, err = selectComponents(cfg)
+	if err != nil || !explicit || len(selected) != 2 || selected[0].Name != "web" || selected[1].Name != "api" {
+		t.Fatalf("expected web then api, got %v, %v, %v", selected, explicit, err)
+	}
+
+	cfg.Components = []string{"docs"}
+	if _, _, err := selectComponents(cfg); err == nil {
+		t.Fatalf("expected an unknown component to be rejected")
+	}
+}
+
+func TestRollbackConfig(t *testing.T) {
+	cases := []struct {
+		name       string
+		tag        string
+		components []string
+		want       string
+		wantErr    bool
+	}{
+		{name: "longest prefix", tag: "api-client-v1.2.0", want: "api-client"},
+		{name: "shorter prefix", tag: "api-v3.0.0", want: "api"},
+		{name: "custom prefix", tag: "web@2.1.0", want: "web"},
+		{name: "explicit component", components: []string{"web"}, want: "web"},
+		{name: "unknown prefix", tag: "docs-v1.0.0", wantErr: true},
+		{name: "no tag or component", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &shared.Config{RollbackTag: c.tag, Components: c.components, Project: shared.Project{Components: testComponents}}
+			scoped, err := rollbackConfig(cfg)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got component %+v", scoped.Component)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("rollbackConfig returned error: %v", err)
+			}
+			if scoped.Component == nil || scoped.Component.Name != c.want {
+				t.Fatalf("expected component %s, got %+v", c.want, scoped.Component)
+			}
+		})
+	}
+
+	cfg := &shared.Config{RollbackTag: "v1.0.0"}
+	if scoped, err := rollbackConfig(cfg); err != nil || scoped != cfg {
+		t.Fatalf("expected the repository configuration without components, got %v", err)
+	}
+}
+
+func TestWithPromptHint(t *testing.T) {
+	err := withPromptHint(fmt.Errorf("component api: %w", &output.MissingDecisionError{Decision: shared.PromptConfirmRelease}))
+	want := "component api: Cannot prompt for confirm_release in non-interactive mode; pass it on the command line or set prompts.confirm_release in .releaser.json"
+	if err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err)
+	}
+	var missing *output.MissingDecisionError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected the hint to keep the MissingDecisionError")
+	}
+	other := errors.New("boom")
+	if withPromptHint(other) != other {
+		t.Fatalf("expected other errors to be left alone")
+	}
+}
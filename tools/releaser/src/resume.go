@@ -0,0 +1,79 @@
+package main
+
+import (
+	"releaser/tool/gitops"
+	"releaser/tool/journal"
+	"releaser/tool/output"
+	"releaser/tool/shared"
+)
+
+// resumeRelease returns the journal entry of the release about to run. An
+// unfinished release of the same component left at the current HEAD is
+// resumed: its versions, notes and release files replace the ones computed
+// so far. Otherwise a new entry is started.
+func resumeRelease(cfg *shared.Config) *journal.Entry {
+	name := componentName(cfg)
+	entry := cfg.Journal.Find(name)
+	if entry == nil {
+		return cfg.Journal.Start(name)
+	}
+	head, err := gitops.Head(cfg.BaseDir)
+	if err != nil || head != entry.Head {
+		output.Warn("Discarding the interrupted release " + entry.NewTag + " from " + cfg.Journal.Path() + ": HEAD has moved since")
+		return cfg.Journal.Start(name)
+	}
+
+	if cfg.TypeSet && cfg.Type != entry.Type {
+		output.Warn("Ignoring release type " + cfg.Type + " while resuming the " + entry.Type + " release " + entry.NewTag)
+	}
+	cfg.Type = entry.Type
+	cfg.TagPrefix = entry.TagPrefix
+	cfg.OldTag = entry.OldTag
+	cfg.OldVer = entry.OldVersion
+	cfg.NewTag = entry.NewTag
+	cfg.NewVer = entry.NewVersion
+	cfg.Prerelease = entry.Prerelease
+	cfg.Changes = entry.Changes
+	cfg.ReleaseFiles = entry.ReleaseFiles
+	return entry
+}
+
+// recordStep journals a completed step with the state the remaining steps
+// need.
+func recordStep(cfg *shared.Config, entry *journal.Entry, step string) {
+	if cfg.Journal == nil {
+		return
+	}
+	head, err := gitops.Head(cfg.BaseDir)
+	if err != nil {
+		output.Warn("Failed to journal release step " + step + ": " + err.Error())
+		return
+	}
+	entry.Type = cfg.Type
+	entry.TagPrefix = cfg.TagPrefix
+	entry.OldTag = cfg.OldTag
+	entry.OldVersion = cfg.OldVer
+	entry.NewTag = cfg.NewTag
+	entry.NewVersion = cfg.NewVer
+	entry.Prerelease = cfg.Prerelease
+	entry.Changes = cfg.Changes
+	entry.ReleaseFiles = cfg.ReleaseFiles
+	entry.Complete(step, head)
+	if err := cfg.Journal.Save(); err != nil {
+		output.Warn("Failed to journal release step " + step + ": " + err.Error())
+	}
+}
+
+// forgetRelease drops the journal entry of a finished or declined release.
+func forgetRelease(cfg *shared.Config) {
+	if err := cfg.Journal.Finish(componentName(cfg)); err != nil {
+		output.Warn("Failed to update " + cfg.Journal.Path() + ": " + err.Error())
+	}
+}
+
+func componentName(cfg *shared.Config) string {
+	if cfg.Component == nil {
+		return ""
+	}
+	return cfg.Component.Name
+}
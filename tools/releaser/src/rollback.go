@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"releaser/tool/journal"
+	"releaser/tool/output"
+	"releaser/tool/release"
+	"releaser/tool/settings"
+	"releaser/tool/shared"
+	"releaser/tool/version"
+)
+
+// rollback undoes a release and records what was removed.
+func rollback(cfg *shared.Config) error {
+	if err := preflight(cfg); err != nil {
+		return err
+	}
+	c, err := rollbackConfig(cfg)
+	if err != nil {
+		return err
+	}
+	if err := version.GetCurrentVersion(c); err != nil {
+		return err
+	}
+	tag := cfg.RollbackTag
+	if tag == "" {
+		tag = c.OldTag
+	}
+
+	record, err := release.Rollback(c, tag, cfg.Revert)
+	if err != nil {
+		if removed := removedParts(record); len(removed) > 0 {
+			output.Warn("Rollback of " + tag + " stopped after removing: " + strings.Join(removed, ", "))
+		}
+		return err
+	}
+	if len(removedParts(record)) > 0 {
+		if err := c.Journal.RecordRollback(record); err != nil {
+			output.Warn("Failed to record the rollback: " + err.Error())
+		}
+	}
+	if entry := c.Journal.Find(componentName(c)); entry != nil && entry.NewTag == tag {
+		forgetRelease(c)
+	}
+
+	if cfg.DryRun {
+		output.Blank()
+		output.Info("Dry run: rollback plan for " + tag)
+		c.Plan.PrintActions()
+		return nil
+	}
+	output.Success("Rolled back " + tag)
+	return nil
+}
+
+// removedParts lists what a rollback removed before it failed.
+func removedParts(record journal.Rollback) []string {
+	var removed []string
+	if record.Release != "" {
+		removed = append(removed, "GitHub release")
+	}
+	if record.RemoteTag {
+		removed = append(removed, "remote tag")
+	}
+	if record.Reverted != "" {
+		removed = append(removed, "release commit (reverted)")
+	}
+	if record.LocalTag {
+		removed = append(removed, "local tag")
+	}
+	return removed
+}
+
+// rollbackConfig scopes the rollback to the component given with
+// --component or owning the tag.
+func rollbackConfig(cfg *shared.Config) (*shared.Config, error) {
+	components := cfg.Project.Components
+	if len(cfg.Components) == 1 {
+		component, ok := findComponent(components, cfg.Components[0])
+		if !ok {
+			return nil, fmt.Errorf("unknown component: %s", cfg.Components[0])
+		}
+		return cfg.ForComponent(component), nil
+	}
+	if len(components) == 0 {
+		return cfg, nil
+	}
+	if cfg.RollbackTag == "" {
+		return nil, fmt.Errorf("rollback needs a tag or --component when components are defined in %s", settings.FileName)
+	}
+	var owner *shared.Component
+	for i, component := range components {
+		if strings.HasPrefix(cfg.RollbackTag, component.TagPrefix) && (owner == nil || len(component.TagPrefix) > len(owner.TagPrefix)) {
+			owner = &components[i]
+		}
+	}
+	if owner == nil {
+		return nil, fmt.Errorf("no component has a tag prefix matching %s", cfg.RollbackTag)
+	}
+	return cfg.ForComponent(*owner), nil
+}
@@ -3,35 +3,125 @@ package cli
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"releaser/tool/output"
 	"releaser/tool/shared"
 )
 
 func Usage(bin string) {
-	fmt.Printf("Usage: %s [major|minor|patch] [--force] [--no-follow] [--verbose|-v|-vv]\n\n", filepath.Base(bin))
+	fmt.Printf("Usage: %s [type] [--preid <id>] [--component <name>|--all] [--force] [--dry-run] [--non-interactive] [--no-follow] [--verbose|-v|-vv]\n", filepath.Base(bin))
+	fmt.Printf("       %s plan [type] [--output <file>] [options]\n", filepath.Base(bin))
+	fmt.Printf("       %s apply [file] [--no-follow] [--verbose|-v|-vv]\n", filepath.Base(bin))
+	fmt.Printf("       %s rollback [tag] [--revert] [--component <name>] [--force] [--yes] [--dry-run]\n\n", filepath.Base(bin))
+	fmt.Println("Commands:")
+	fmt.Println("  plan                Run the checks, detection and release notes, and write")
+	fmt.Println("                      the release plan for review (default .git/releaser/plan.json),")
+	fmt.Println("                      signed with the RELEASER_PLAN_KEY secret.")
+	fmt.Println("  apply [file]        Publish a reviewed plan: commit, tag and create the GitHub")
+	fmt.Println("                      release, provided its signature matches RELEASER_PLAN_KEY")
+	fmt.Println("                      and HEAD is still the planned commit.")
+	fmt.Println("  rollback [tag]      Delete the GitHub release and the tags of the latest")
+	fmt.Println("                      release, or of an older tag with --force.")
 	fmt.Println("Arguments:")
 	fmt.Println("  major|minor|patch   Optional release type. If omitted, it will be detected")
 	fmt.Println("                      from git diff (like your Laravel command). When provided,")
 	fmt.Println("                      the confirmation prompt is skipped.")
+	fmt.Println("  premajor|preminor|prepatch")
+	fmt.Println("                      Start a pre-release of the next major, minor or patch")
+	fmt.Println("                      version, e.g. 2.0.0-rc.0 with --preid rc.")
+	fmt.Println("  prerelease          Increment the current pre-release (2.0.0-rc.0 → 2.0.0-rc.1).")
+	fmt.Println("  release             Drop the pre-release identifiers (2.0.0-rc.1 → 2.0.0).")
 	fmt.Println("Options:")
-	fmt.Println("  --force             Don't ask confirmation before creating the tag.")
+	fmt.Println("  --preid <id>        Pre-release identifier for the pre* types (alpha, beta, rc...).")
+	fmt.Println("  --component <name>  Release a monorepo component; repeat or comma-separate")
+	fmt.Println("                      to release several.")
+	fmt.Println("  --all               Release every component changed since its last tag.")
+	fmt.Println("  --force             Don't ask confirmation before creating the tag; with")
+	fmt.Println("                      rollback, allow rolling back a tag other than the latest.")
+	fmt.Println("  --yes, -y           Answer yes to the confirmation prompts.")
+	fmt.Println("  --output <file>     Plan file written by the plan command.")
+	fmt.Println("  --revert            Also revert the release commit when rolling back.")
+	fmt.Println("  --dry-run           Print the release plan without writing, tagging, pushing")
+	fmt.Println("                      or publishing; fails when a preflight check fails.")
+	fmt.Println("  --non-interactive   Never prompt: answer from the prompts settings or fail.")
+	fmt.Println("                      Implied when CI=true or stdin is not a terminal.")
 	fmt.Println("  --no-follow         Don't check the GitHub Actions workflow after publishing.")
 	fmt.Println("  --verbose, -v       Enable verbose output.")
 	fmt.Println("  -vv                 Enable very verbose output (trace-level).")
 }
 
 func ParseArgs(cfg *shared.Config, args []string, bin string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "plan":
+			cfg.Command = args[0]
+			args = args[1:]
+		case "apply":
+			cfg.Command = args[0]
+			args = args[1:]
+			if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+				cfg.PlanFile = args[0]
+				args = args[1:]
+			}
+		case "rollback":
+			cfg.Command = args[0]
+			args = args[1:]
+			if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+				cfg.RollbackTag = args[0]
+				args = args[1:]
+			}
+		}
+	}
 	for len(args) > 0 {
 		switch args[0] {
-		case "major", "minor", "patch":
+		case "major", "minor", "patch", "premajor", "preminor", "prepatch", "prerelease", "release":
 			cfg.Type = args[0]
 			cfg.TypeSet = true
 			cfg.Force = true
 			args = args[1:]
+		case "--preid":
+			if len(args) < 2 {
+				return fmt.Errorf("Missing value for --preid")
+			}
+			cfg.PreID = args[1]
+			args = args[2:]
+		case "--component":
+			if len(args) < 2 {
+				return fmt.Errorf("Missing value for --component")
+			}
+			cfg.Components = append(cfg.Components, splitList(args[1])...)
+			args = args[2:]
+		case "--all":
+			cfg.AllComponents = true
+			args = args[1:]
 		case "--force":
 			cfg.Force = true
 			args = args[1:]
+		case "--output", "-o":
+			if cfg.Command != "plan" {
+				return fmt.Errorf("%s is only supported by the plan command", args[0])
+			}
+			if len(args) < 2 {
+				return fmt.Errorf("Missing value for %s", args[0])
+			}
+			cfg.PlanFile = args[1]
+			args = args[2:]
+		case "--revert":
+			if cfg.Command != "rollback" {
+				return fmt.Errorf("--revert is only supported by the rollback command")
+			}
+			cfg.Revert = true
+			args = args[1:]
+		case "--dry-run":
+			cfg.DryRun = true
+			args = args[1:]
+		case "--non-interactive":
+			cfg.NonInteractive = true
+			args = args[1:]
+		case "--yes", "-y":
+			cfg.Yes = true
+			args = args[1:]
 		case "--no-follow":
 			cfg.Follow = false
 			args = args[1:]
@@ -52,15 +142,44 @@ func ParseArgs(cfg *shared.Config, args []string, bin string) error {
 			Usage(bin)
 			output.Exit(0)
 		default:
+			if value, ok := strings.CutPrefix(args[0], "--preid="); ok {
+				cfg.PreID = value
+				args = args[1:]
+				continue
+			}
+			if value, ok := strings.CutPrefix(args[0], "--component="); ok {
+				cfg.Components = append(cfg.Components, splitList(value)...)
+				args = args[1:]
+				continue
+			}
 			return fmt.Errorf("Unknown argument: %s", args[0])
 		}
 	}
+	if cfg.Command == "apply" && (cfg.TypeSet || cfg.PreID != "" || cfg.DryRun || len(cfg.Components) > 0 || cfg.AllComponents) {
+		return fmt.Errorf("apply takes its release from the plan file; only --no-follow and verbosity flags are allowed")
+	}
+	if cfg.Command == "rollback" && (cfg.TypeSet || cfg.PreID != "" || cfg.AllComponents || len(cfg.Components) > 1) {
+		return fmt.Errorf("rollback takes a tag and at most one --component")
+	}
+	if cfg.AllComponents && len(cfg.Components) > 0 {
+		return fmt.Errorf("--all cannot be combined with --component")
+	}
 	return nil
 }
 
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func IsValidType(t string) bool {
 	switch t {
-	case "major", "minor", "patch":
+	case "major", "minor", "patch", "premajor", "preminor", "prepatch", "prerelease", "release":
 		return true
 	default:
 		return false
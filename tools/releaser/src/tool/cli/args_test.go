@@ -38,3 +38,102 @@ func TestParseArgs_ForceFlagWithoutType(t *testing.T) {
 		t.Fatalf("expected TypeSet=false")
 	}
 }
+
+func TestParseArgs_PrereleaseTypeWithPreID(t *testing.T) {
+	for _, args := range [][]string{{"premajor", "--preid", "rc"}, {"--preid=rc", "premajor"}} {
+		cfg := &shared.Config{Follow: true}
+		if err := ParseArgs(cfg, args, "releaser"); err != nil {
+			t.Fatalf("ParseArgs returned error: %v", err)
+		}
+		if cfg.Type != "premajor" || cfg.PreID != "rc" || !cfg.TypeSet {
+			t.Fatalf("unexpected config for %v: %+v", args, cfg)
+		}
+		if !IsValidType(cfg.Type) {
+			t.Fatalf("expected premajor to be a valid type")
+		}
+	}
+
+	cfg := &shared.Config{}
+	if err := ParseArgs(cfg, []string{"--preid"}, "releaser"); err == nil {
+		t.Fatalf("expected an error for --preid without a value")
+	}
+}
+
+func TestParseArgs_Components(t *testing.T) {
+	cfg := &shared.Config{}
+	if err := ParseArgs(cfg, []string{"--component", "billing", "--component=search,auth"}, "releaser"); err != nil {
+		t.Fatalf("ParseArgs returned error: %v", err)
+	}
+	if len(cfg.Components) != 3 || cfg.Components[0] != "billing" || cfg.Components[2] != "auth" {
+		t.Fatalf("unexpected components %v", cfg.Components)
+	}
+
+	if err := ParseArgs(&shared.Config{}, []string{"--all", "--component", "billing"}, "releaser"); err == nil {
+		t.Fatalf("expected --all and --component to be exclusive")
+	}
+}
+
+func TestParseArgs_DryRun(t *testing.T) {
+	cfg := &shared.Config{Follow: true}
+	if err := ParseArgs(cfg, []string{"minor", "--dry-run"}, "releaser"); err != nil {
+		t.Fatalf("ParseArgs returned error: %v", err)
+	}
+	if !cfg.DryRun {
+		t.Fatalf("expected DryRun=true")
+	}
+	if cfg.Type != "minor" {
+		t.Fatalf("expected type minor, got %s", cfg.Type)
+	}
+}
+
+func TestParseArgs_PlanAndApply(t *testing.T) {
+	cfg := &shared.Config{}
+	if err := ParseArgs(cfg, []string{"plan", "minor", "--output", "plan.json"}, "releaser"); err != nil {
+		t.Fatalf("ParseArgs returned error: %v", err)
+	}
+	if cfg.Command != "plan" || cfg.PlanFile != "plan.json" || cfg.Type != "minor" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	cfg = &shared.Config{}
+	if err := ParseArgs(cfg, []string{"apply", "plan.json", "--no-follow"}, "releaser"); err != nil {
+		t.Fatalf("ParseArgs returned error: %v", err)
+	}
+	if cfg.Command != "apply" || cfg.PlanFile != "plan.json" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	if err := ParseArgs(&shared.Config{}, []string{"apply", "plan.json", "major"}, "releaser"); err == nil {
+		t.Fatalf("expected apply to reject a release type")
+	}
+	if err := ParseArgs(&shared.Config{}, []string{"--output", "plan.json"}, "releaser"); err == nil {
+		t.Fatalf("expected --output outside plan to be rejected")
+	}
+}
+
+func TestParseArgs_Rollback(t *testing.T) {
+	cfg := &shared.Config{}
+	if err := ParseArgs(cfg, []string{"rollback", "v1.2.0", "--revert", "--force"}, "releaser"); err != nil {
+		t.Fatalf("ParseArgs returned error: %v", err)
+	}
+	if cfg.Command != "rollback" || cfg.RollbackTag != "v1.2.0" || !cfg.Revert || !cfg.Force {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	if err := ParseArgs(&shared.Config{}, []string{"--revert"}, "releaser"); err == nil {
+		t.Fatalf("expected --revert outside rollback to be rejected")
+	}
+	if err := ParseArgs(&shared.Config{}, []string{"rollback", "--all"}, "releaser"); err == nil {
+		t.Fatalf("expected rollback to reject --all")
+	}
+}
+
+func TestParseArgs_Yes(t *testing.T) {
+	cfg := &shared.Config{}
+	if err := ParseArgs(cfg, []string{"rollback", "-y"}, "releaser"); err != nil {
+		t.Fatalf("ParseArgs returned error: %v", err)
+	}
+	if !cfg.Yes || cfg.Force {
+		t.Fatalf("expected --yes without --force, got %+v", cfg)
+	}
+}
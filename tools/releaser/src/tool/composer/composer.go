@@ -0,0 +1,286 @@
+package composer
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Manifest is the subset of composer.json that affects consumers of a
+// package.
+type Manifest struct {
+	Name       string            `json:"name"`
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+	Conflict   map[string]string `json:"conflict"`
+	Replace    map[string]string `json:"replace"`
+	Autoload   Autoload          `json:"autoload"`
+}
+
+// Autoload lists the PSR-4 and PSR-0 namespace prefixes of a package. Paths
+// may be a single directory or a list of directories.
+type Autoload struct {
+	PSR4 map[string]any `json:"psr-4"`
+	PSR0 map[string]any `json:"psr-0"`
+}
+
+// Lock is the subset of composer.lock needed to list installed versions.
+type Lock struct {
+	Packages    []LockedPackage `json:"packages"`
+	PackagesDev []LockedPackage `json:"packages-dev"`
+}
+
+type LockedPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Upgrade is a package whose locked version differs between two lock files.
+// From is empty for newly installed packages and To for removed ones.
+type Upgrade struct {
+	Name string
+	From string
+	To   string
+	Dev  bool
+}
+
+// ParseManifest decodes a composer.json document.
+func ParseManifest(src []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.Unmarshal(src, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ParseLock decodes a composer.lock document.
+func ParseLock(src []byte) (*Lock, error) {
+	l := &Lock{}
+	if err := json.Unmarshal(src, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Namespaces returns the autoloaded namespace prefixes mapped to their paths.
+func (a Autoload) Namespaces() map[string]string {
+	out := make(map[string]string)
+	for _, section := range []map[string]any{a.PSR4, a.PSR0} {
+		for namespace, paths := range section {
+			out[namespace] = autoloadPaths(paths)
+		}
+	}
+	return out
+}
+
+func autoloadPaths(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case []any:
+		var parts []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+// Upgrades lists the packages whose locked version changed, sorted by name
+// with runtime packages before dev packages.
+func Upgrades(oldLock, newLock *Lock) []Upgrade {
+	var out []Upgrade
+	for _, dev := range []bool{false, true} {
+		oldVersions := oldLock.versions(dev)
+		newVersions := newLock.versions(dev)
+		names := make(map[string]bool)
+		for name := range oldVersions {
+			names[name] = true
+		}
+		for name := range newVersions {
+			names[name] = true
+		}
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+		for _, name := range sorted {
+			if oldVersions[name] != newVersions[name] {
+				out = append(out, Upgrade{Name: name, From: oldVersions[name], To: newVersions[name], Dev: dev})
+			}
+		}
+	}
+	return out
+}
+
+// String renders the upgrade as "name old → new".
+func (u Upgrade) String() string {
+	line := u.Name + " "
+	switch {
+	case u.From == "":
+		line += "added " + u.To
+	case u.To == "":
+		line += "removed " + u.From
+	default:
+		line += u.From + " → " + u.To
+	}
+	if u.Dev {
+		line += " (dev)"
+	}
+	return line
+}
+
+func (l *Lock) versions(dev bool) map[string]string {
+	out := make(map[string]string)
+	if l == nil {
+		return out
+	}
+	packages := l.Packages
+	if dev {
+		packages = l.PackagesDev
+	}
+	for _, pkg := range packages {
+		out[pkg.Name] = pkg.Version
+	}
+	return out
+}
+
+// IsPlatform reports whether name is a platform requirement such as php,
+// ext-json or lib-curl rather than an installable package.
+func IsPlatform(name string) bool {
+	return name == "php" || name == "php-64bit" || name == "hhvm" || name == "composer-plugin-api" ||
+		strings.HasPrefix(name, "ext-") || strings.HasPrefix(name, "lib-")
+}
+
+// MinimumVersion returns the lowest version allowed by a constraint such as
+// "^8.1", "~7.4 || ^8.0", ">=8.1 <9.0" or "10.*". Constraints without a lower
+// bound, like "*" or "dev-main", report false.
+func MinimumVersion(constraint string) (Version, bool) {
+	var lowest Version
+	found := false
+	for _, alternative := range splitAlternatives(constraint) {
+		bound, ok := alternativeLowerBound(alternative)
+		if !ok {
+			return Version{}, false
+		}
+		if !found || bound.Compare(lowest) < 0 {
+			lowest = bound
+			found = true
+		}
+	}
+	return lowest, found
+}
+
+func splitAlternatives(constraint string) []string {
+	constraint = strings.ReplaceAll(constraint, "||", "|")
+	var out []string
+	for _, part := range strings.Split(constraint, "|") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// alternativeLowerBound returns the greatest lower bound among the
+// conjunctive parts of one alternative.
+func alternativeLowerBound(alternative string) (Version, bool) {
+	if from, _, ok := strings.Cut(alternative, " - "); ok {
+		return ParseVersion(from)
+	}
+
+	var bound Version
+	found := false
+	for _, part := range strings.FieldsFunc(alternative, func(r rune) bool { return r == ' ' || r == ',' }) {
+		part, _, _ = strings.Cut(part, "@")
+		var v Version
+		var ok bool
+		switch {
+		case strings.HasPrefix(part, "<"), strings.HasPrefix(part, "!="):
+			continue
+		case strings.HasPrefix(part, ">="):
+			v, ok = ParseVersion(part[2:])
+		case strings.HasPrefix(part, ">"):
+			v, ok = ParseVersion(part[1:])
+		case strings.HasPrefix(part, "^"), strings.HasPrefix(part, "~"), strings.HasPrefix(part, "="):
+			v, ok = ParseVersion(part[1:])
+		default:
+			v, ok = ParseVersion(part)
+		}
+		if !ok {
+			continue
+		}
+		if !found || v.Compare(bound) > 0 {
+			bound = v
+			found = true
+		}
+	}
+	return bound, found
+}
+
+// Version is a numeric version with up to four segments.
+type Version [4]int
+
+// ParseVersion reads versions such as "8.1", "v2.0.3" or "10.*". Branch
+// names and wildcards without a leading number are rejected.
+func ParseVersion(s string) (Version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "-")
+	var v Version
+	parts := strings.Split(s, ".")
+	for i, part := range parts {
+		if i >= len(v) {
+			break
+		}
+		if part == "*" || part == "x" || part == "X" {
+			if i == 0 {
+				return Version{}, false
+			}
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+func (v Version) Major() int {
+	return v[0]
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or greater
+// than other.
+func (v Version) Compare(other Version) int {
+	for i := range v {
+		switch {
+		case v[i] < other[i]:
+			return -1
+		case v[i] > other[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v Version) String() string {
+	last := 1
+	for i := len(v) - 1; i > 1; i-- {
+		if v[i] != 0 {
+			last = i
+			break
+		}
+	}
+	parts := make([]string, 0, last+1)
+	for _, n := range v[:last+1] {
+		parts = append(parts, strconv.Itoa(n))
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,62 @@
+package composer
+
+import "testing"
+
+func TestMinimumVersion(t *testing.T) {
+	for constraint, expected := range map[string]string{
+		"^8.1":          "8.1",
+		"~7.4 || ^8.0":  "7.4",
+		"^10.0|^11.0":   "10.0",
+		">=8.1 <9.0":    "8.1",
+		"10.*":          "10.0",
+		"v2.3.1":        "2.3.1",
+		"1.0 - 2.0":     "1.0",
+		"^1.2@beta":     "1.2",
+		">=7.4, <8.3":   "7.4",
+		"^8.2.0-alpha1": "8.2",
+	} {
+		v, ok := MinimumVersion(constraint)
+		if !ok {
+			t.Fatalf("MinimumVersion(%q) reported no bound", constraint)
+		}
+		if v.String() != expected {
+			t.Fatalf("MinimumVersion(%q) = %s, expected %s", constraint, v, expected)
+		}
+	}
+
+	for _, constraint := range []string{"*", "dev-main", "<2.0"} {
+		if _, ok := MinimumVersion(constraint); ok {
+			t.Fatalf("expected no lower bound for %q", constraint)
+		}
+	}
+}
+
+func TestUpgrades(t *testing.T) {
+	oldLock := &Lock{
+		Packages:    []LockedPackage{{"laravel/framework", "v10.1.0"}, {"guzzlehttp/guzzle", "7.5.0"}, {"old/pkg", "1.0.0"}},
+		PackagesDev: []LockedPackage{{"phpunit/phpunit", "10.0.0"}},
+	}
+	newLock := &Lock{
+		Packages:    []LockedPackage{{"laravel/framework", "v10.2.0"}, {"guzzlehttp/guzzle", "7.5.0"}, {"new/pkg", "2.0.0"}},
+		PackagesDev: []LockedPackage{{"phpunit/phpunit", "10.1.0"}},
+	}
+
+	var got []string
+	for _, upgrade := range Upgrades(oldLock, newLock) {
+		got = append(got, upgrade.String())
+	}
+	expected := []string{
+		"laravel/framework v10.1.0 → v10.2.0",
+		"new/pkg added 2.0.0",
+		"old/pkg removed 1.0.0",
+		"phpunit/phpunit 10.0.0 → 10.1.0 (dev)",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package conventional
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Commit is a commit message following the Conventional Commits format
+// `type(scope)!: subject`, with an optional `BREAKING CHANGE:` footer.
+type Commit struct {
+	Type         string
+	Scope        string
+	Subject      string
+	Breaking     bool
+	BreakingNote string
+}
+
+// Section is a release notes heading and the commit types listed under it.
+type Section struct {
+	Title string
+	Types []string
+}
+
+// BreakingTitle is the heading breaking commits are listed under, whatever
+// their type.
+const BreakingTitle = "Breaking Changes"
+
+// OtherTitle is the heading for conventional types without a section of their
+// own and for commits that do not follow the format.
+const OtherTitle = "Other Changes"
+
+// Sections lists the release notes headings in display order.
+var Sections = []Section{
+	{Title: "Features", Types: []string{"feat"}},
+	{Title: "Fixes", Types: []string{"fix"}},
+	{Title: "Performance", Types: []string{"perf"}},
+	{Title: "Refactoring", Types: []string{"refactor"}},
+	{Title: "Documentation", Types: []string{"docs"}},
+	{Title: "Tests", Types: []string{"test"}},
+	{Title: "Build & CI", Types: []string{"build", "ci"}},
+	{Title: "Chores", Types: []string{"chore", "style"}},
+	{Title: "Reverts", Types: []string{"revert"}},
+}
+
+var headerPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?:\s+(.+)$`)
+
+var footerPattern = regexp.MustCompile(`^([A-Za-z-]+|BREAKING CHANGE)(?::\s|\s#)`)
+
+// Parse reads a commit subject and body. The boolean is false when the
+// subject does not follow the Conventional Commits header format or its type
+// is neither one of the Sections types nor one of extraTypes, so subjects
+// such as "WIP: ..." or "Note: ..." are not mistaken for conventional ones.
+func Parse(subject, body string, extraTypes ...string) (Commit, bool) {
+	match := headerPattern.FindStringSubmatch(strings.TrimSpace(subject))
+	if match == nil || !knownType(match[1], extraTypes) {
+		return Commit{}, false
+	}
+	c := Commit{
+		Type:     strings.ToLower(match[1]),
+		Scope:    strings.TrimSpace(match[2]),
+		Subject:  strings.TrimSpace(match[4]),
+		Breaking: match[3] == "!",
+	}
+	if note, ok := breakingFooter(body); ok {
+		c.Breaking = true
+		c.BreakingNote = note
+	}
+	return c, true
+}
+
+// knownType reports whether t is a standard commit type or one of extra.
+func knownType(t string, extra []string) bool {
+	for _, section := range Sections {
+		for _, known := range section.Types {
+			if strings.EqualFold(t, known) {
+				return true
+			}
+		}
+	}
+	for _, known := range extra {
+		if strings.EqualFold(t, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// breakingFooter returns the text of a `BREAKING CHANGE:` or
+// `BREAKING-CHANGE:` footer, including its continuation lines.
+func breakingFooter(body string) (string, bool) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		var rest string
+		switch {
+		case strings.HasPrefix(line, "BREAKING CHANGE:"):
+			rest = strings.TrimPrefix(line, "BREAKING CHANGE:")
+		case strings.HasPrefix(line, "BREAKING-CHANGE:"):
+			rest = strings.TrimPrefix(line, "BREAKING-CHANGE:")
+		default:
+			continue
+		}
+		note := []string{strings.TrimSpace(rest)}
+		for _, next := range lines[i+1:] {
+			next = strings.TrimRight(next, "\r")
+			if strings.TrimSpace(next) == "" || footerPattern.MatchString(next) {
+				break
+			}
+			note = append(note, strings.TrimSpace(next))
+		}
+		return strings.TrimSpace(strings.Join(note, " ")), true
+	}
+	return "", false
+}
+
+// Bump returns the release type the commit calls for: major for breaking
+// changes, minor for features and patch for everything else.
+func (c Commit) Bump() string {
+	switch {
+	case c.Breaking:
+		return "major"
+	case c.Type == "feat":
+		return "minor"
+	default:
+		return "patch"
+	}
+}
+
+// SectionTitle returns the release notes heading the commit belongs under.
+func (c Commit) SectionTitle() string {
+	if c.Breaking {
+		return BreakingTitle
+	}
+	for _, section := range Sections {
+		for _, t := range section.Types {
+			if t == c.Type {
+				return section.Title
+			}
+		}
+	}
+	return OtherTitle
+}
+
+// Header renders the commit header back as `type(scope)!: subject`.
+func (c Commit) Header() string {
+	header := c.Type
+	if c.Scope != "" {
+		header += "(" + c.Scope + ")"
+	}
+	if c.Breaking {
+		header += "!"
+	}
+	return header + ": " + c.Subject
+}
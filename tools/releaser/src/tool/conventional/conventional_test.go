@@ -0,0 +1,69 @@
+package conventional
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		subject  string
+		body     string
+		ok       bool
+		expected Commit
+		bump     string
+	}{
+		{subject: "feat(api): add bulk export", ok: true, expected: Commit{Type: "feat", Scope: "api", Subject: "add bulk export"}, bump: "minor"},
+		{subject: "fix: handle empty tags", ok: true, expected: Commit{Type: "fix", Subject: "handle empty tags"}, bump: "patch"},
+		{subject: "refactor(core)!: drop legacy driver", ok: true, expected: Commit{Type: "refactor", Scope: "core", Subject: "drop legacy driver", Breaking: true}, bump: "major"},
+		{
+			subject:  "Feat: new config loader",
+			body:     "Longer description.\n\nBREAKING CHANGE: the `paths` key\nis now required.\nRefs: #12",
+			ok:       true,
+			expected: Commit{Type: "feat", Subject: "new config loader", Breaking: true, BreakingNote: "the `paths` key is now required."},
+			bump:     "major",
+		},
+		{subject: "Merge pull request #4 from acme/feature", ok: false},
+		{subject: "Update README.md", ok: false},
+		{subject: "WIP: half of the export", ok: false},
+		{subject: "Note: this reverts the hotfix", ok: false},
+		{subject: "Merge: acme/main into release", ok: false},
+	}
+
+	for _, tt := range tests {
+		c, ok := Parse(tt.subject, tt.body)
+		if ok != tt.ok {
+			t.Fatalf("Parse(%q) ok = %v, expected %v", tt.subject, ok, tt.ok)
+		}
+		if !ok {
+			continue
+		}
+		if c != tt.expected {
+			t.Fatalf("Parse(%q) = %+v, expected %+v", tt.subject, c, tt.expected)
+		}
+		if c.Bump() != tt.bump {
+			t.Fatalf("Parse(%q).Bump() = %s, expected %s", tt.subject, c.Bump(), tt.bump)
+		}
+	}
+}
+
+func TestParse_ExtraTypes(t *testing.T) {
+	if _, ok := Parse("deps: bump guzzle", ""); ok {
+		t.Fatalf("expected deps to be rejected without configuration")
+	}
+	c, ok := Parse("Deps(php): bump guzzle", "", "deps")
+	if !ok || c.Type != "deps" || c.SectionTitle() != OtherTitle {
+		t.Fatalf("expected a configured deps commit under %s, got %+v (ok %v)", OtherTitle, c, ok)
+	}
+}
+
+func TestSectionTitle(t *testing.T) {
+	for commit, expected := range map[Commit]string{
+		{Type: "feat"}:                "Features",
+		{Type: "fix"}:                 "Fixes",
+		{Type: "ci"}:                  "Build & CI",
+		{Type: "fix", Breaking: true}: BreakingTitle,
+		{Type: "wip"}:                 OtherTitle,
+	} {
+		if got := commit.SectionTitle(); got != expected {
+			t.Fatalf("SectionTitle(%+v) = %s, expected %s", commit, got, expected)
+		}
+	}
+}
@@ -11,6 +11,11 @@ import (
 	"releaser/tool/output"
 )
 
+// IsCI reports whether releaser runs in a CI job, such as GitHub Actions.
+func IsCI() bool {
+	return os.Getenv("CI") == "true"
+}
+
 func Load() error {
 	if _, err := os.Stat(".env"); err == nil {
 		output.Verbose("Found .env file in current directory")
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -16,38 +17,76 @@ import (
 	"releaser/tool/shared"
 )
 
+// apiURL is the base URL of the GitHub REST API.
+var apiURL = "https://api.github.com"
+
 func GetCurrentVersion(cfg *shared.Config) error {
 	label := "Fetching latest GitHub release"
 	output.Info(label + "...")
 	output.Verbose("Repository: " + cfg.Repo)
 
-	resp, err := request("GET", "https://api.github.com/repos/"+cfg.Repo+"/releases/latest", cfg.Token, nil)
+	tag, err := LatestReleaseTag(cfg)
 	if err != nil {
 		output.ReplaceLastLine(label + " ⚠")
-		output.Warn("Failed to call GitHub API for latest release")
+		output.Warn("Failed to fetch latest tag from GitHub")
 		return err
 	}
 
+	cfg.OldTag = tag
+	cfg.OldVer = strings.TrimPrefix(cfg.OldTag, "v")
+	cfg.TagPrefix = strings.TrimSuffix(cfg.OldTag, cfg.OldVer)
+	output.ReplaceLastLine(label + ": " + cfg.OldTag + " ✔")
+	return nil
+}
+
+// LatestReleaseTag returns the tag of the latest published, non-pre-release
+// GitHub release.
+func LatestReleaseTag(cfg *shared.Config) (string, error) {
+	resp, err := request("GET", apiURL+"/repos/"+cfg.Repo+"/releases/latest", cfg.Token, nil)
+	if err != nil {
+		return "", err
+	}
+
 	var payload struct {
 		TagName string `json:"tag_name"`
 	}
 	if err := json.Unmarshal(resp, &payload); err != nil {
-		output.ReplaceLastLine(label + " ⚠")
-		output.Warn("Failed to fetch latest tag from GitHub")
-		fmt.Println(string(resp))
-		return err
+		return "", fmt.Errorf("unexpected response %s: %w", strings.TrimSpace(string(resp)), err)
 	}
 	if payload.TagName == "" {
-		output.ReplaceLastLine(label + " ⚠")
-		output.Warn("Failed to fetch latest tag from GitHub")
-		fmt.Println(string(resp))
-		return errors.New("missing tag_name")
+		return "", fmt.Errorf("missing tag_name in response %s", strings.TrimSpace(string(resp)))
 	}
+	return payload.TagName, nil
+}
 
-	cfg.OldTag = payload.TagName
-	cfg.OldVer = strings.TrimPrefix(cfg.OldTag, "v")
-	output.ReplaceLastLine(label + ": " + cfg.OldTag + " ✔")
-	return nil
+// ReleaseTags returns the tags of the published, non-pre-release GitHub
+// releases, newest first, as /releases/latest would consider them.
+func ReleaseTags(cfg *shared.Config) ([]string, error) {
+	const perPage = 100
+	var tags []string
+	for page := 1; page <= 10; page++ {
+		resp, err := request("GET", fmt.Sprintf("%s/repos/%s/releases?per_page=%d&page=%d", apiURL, cfg.Repo, perPage, page), cfg.Token, nil)
+		if err != nil {
+			return nil, err
+		}
+		var releases []struct {
+			TagName    string `json:"tag_name"`
+			Draft      bool   `json:"draft"`
+			Prerelease bool   `json:"prerelease"`
+		}
+		if err := json.Unmarshal(resp, &releases); err != nil {
+			return nil, fmt.Errorf("unexpected response %s: %w", strings.TrimSpace(string(resp)), err)
+		}
+		for _, release := range releases {
+			if !release.Draft && !release.Prerelease {
+				tags = append(tags, release.TagName)
+			}
+		}
+		if len(releases) < perPage {
+			break
+		}
+	}
+	return tags, nil
 }
 
 func CreateRelease(cfg *shared.Config) error {
@@ -58,14 +97,19 @@ func CreateRelease(cfg *shared.Config) error {
 		"name":       cfg.NewTag,
 		"body":       cfg.Changes,
 		"draft":      false,
-		"prerelease": false,
+		"prerelease": cfg.Prerelease,
 	}
 	b, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
 
-	resp, err := request("POST", "https://api.github.com/repos/"+cfg.Repo+"/releases", cfg.Token, b)
+	endpoint := apiURL + "/repos/" + cfg.Repo + "/releases"
+	if cfg.Plan != nil {
+		cfg.Plan.Request("POST", endpoint, b)
+		return nil
+	}
+	resp, err := request("POST", endpoint, cfg.Token, b)
 	if err != nil {
 		output.Warn("Failed to call GitHub API for release creation")
 		return err
@@ -91,6 +135,35 @@ func CreateRelease(cfg *shared.Config) error {
 	return nil
 }
 
+// DeleteRelease deletes the GitHub release of tag and returns its URL. It
+// returns an empty URL when the tag has no release.
+func DeleteRelease(cfg *shared.Config, tag string) (string, error) {
+	resp, err := request("GET", apiURL+"/repos/"+cfg.Repo+"/releases/tags/"+url.PathEscape(tag), cfg.Token, nil)
+	if isNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var release struct {
+		ID      int64  `json:"id"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(resp, &release); err != nil {
+		return "", fmt.Errorf("unexpected response %s: %w", strings.TrimSpace(string(resp)), err)
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/releases/%d", apiURL, cfg.Repo, release.ID)
+	if cfg.Plan != nil {
+		cfg.Plan.Request("DELETE", endpoint, nil)
+		return release.HTMLURL, nil
+	}
+	if _, err := request("DELETE", endpoint, cfg.Token, nil); err != nil {
+		return "", err
+	}
+	return release.HTMLURL, nil
+}
+
 func FollowReleaseWorkflow(cfg *shared.Config) error {
 	label := "Following release workflow status"
 	output.Info(label + "...")
@@ -234,7 +307,7 @@ func startEnterListener() <-chan struct{} {
 }
 
 func latestReleaseWorkflowRun(cfg *shared.Config, since time.Time) (workflowRun, bool, error) {
-	resp, err := request("GET", "https://api.github.com/repos/"+cfg.Repo+"/actions/runs?event=release&per_page=20", cfg.Token, nil)
+	resp, err := request("GET", apiURL+"/repos/"+cfg.Repo+"/actions/runs?event=release&per_page=20", cfg.Token, nil)
 	if err != nil {
 		return workflowRun{}, false, err
 	}
@@ -261,7 +334,7 @@ func latestReleaseWorkflowRun(cfg *shared.Config, since time.Time) (workflowRun,
 }
 
 func getWorkflowRun(cfg *shared.Config, id int64) (workflowRun, error) {
-	resp, err := request("GET", fmt.Sprintf("https://api.github.com/repos/%s/actions/runs/%d", cfg.Repo, id), cfg.Token, nil)
+	resp, err := request("GET", fmt.Sprintf("%s/repos/%s/actions/runs/%d", apiURL, cfg.Repo, id), cfg.Token, nil)
 	if err != nil {
 		return workflowRun{}, err
 	}
@@ -350,8 +423,32 @@ func request(method, url, token string, body []byte) ([]byte, error) {
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		output.Verbose("GitHub API non-success status: " + resp.Status)
-		return b, fmt.Errorf("github api status %s", resp.Status)
+		return b, &statusError{code: resp.StatusCode, status: resp.Status}
 	}
 	output.Verbose("GitHub API response status: " + resp.Status)
 	return b, nil
 }
+
+// ErrRateLimited matches the errors of requests refused by the rate limit.
+var ErrRateLimited = errors.New("github api rate limit reached")
+
+// statusError is a non-success response of the GitHub API.
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string {
+	return "github api status " + e.status
+}
+
+// Is matches ErrRateLimited for 403 and 429 responses, which GitHub returns
+// once the primary or secondary rate limit is reached.
+func (e *statusError) Is(target error) bool {
+	return target == ErrRateLimited && (e.code == http.StatusForbidden || e.code == http.StatusTooManyRequests)
+}
+
+func isNotFound(err error) bool {
+	var status *statusError
+	return errors.As(err, &status) && status.code == http.StatusNotFound
+}
@@ -0,0 +1,63 @@
+package githubapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"releaser/tool/shared"
+)
+
+func TestReleaseTags_SkipsDraftsAndPrereleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/app/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("page") != "1" {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"tag_name": "search-v2.0.0"},
+			{"tag_name": "billing-v1.5.0", "draft": true},
+			{"tag_name": "billing-v1.5.0-rc.1", "prerelease": true},
+			{"tag_name": "billing-v1.4.0"}
+		]`)
+	}))
+	defer server.Close()
+	restore := apiURL
+	apiURL = server.URL
+	t.Cleanup(func() { apiURL = restore })
+
+	tags, err := ReleaseTags(&shared.Config{Repo: "acme/app"})
+	if err != nil {
+		t.Fatalf("ReleaseTags returned error: %v", err)
+	}
+	if want := []string{"search-v2.0.0", "billing-v1.4.0"}; !reflect.DeepEqual(tags, want) {
+		t.Fatalf("expected %v, got %v", want, tags)
+	}
+}
+
+func TestPullsForCommit_RateLimited(t *testing.T) {
+	for _, code := range []int{http.StatusForbidden, http.StatusTooManyRequests} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(code)
+		}))
+		restore := apiURL
+		apiURL = server.URL
+
+		_, err := PullsForCommit(&shared.Config{Repo: "acme/app"}, "c1")
+		apiURL = restore
+		server.Close()
+		if !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("status %d: expected ErrRateLimited, got %v", code, err)
+		}
+	}
+	if errors.Is(&statusError{code: http.StatusNotFound}, ErrRateLimited) {
+		t.Fatalf("expected a 404 not to match ErrRateLimited")
+	}
+}
@@ -0,0 +1,81 @@
+package githubapi
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"releaser/tool/shared"
+)
+
+// PullRequest is a merged pull request associated with a released commit.
+type PullRequest struct {
+	Number   int
+	Title    string
+	URL      string
+	Login    string
+	Labels   []string
+	MergedAt string
+}
+
+type pullRequestPayload struct {
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	HTMLURL  string `json:"html_url"`
+	MergedAt string `json:"merged_at"`
+	User     struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// PullsForCommit returns the merged pull requests that contain sha.
+func PullsForCommit(cfg *shared.Config, sha string) ([]PullRequest, error) {
+	resp, err := request("GET", apiURL+"/repos/"+cfg.Repo+"/commits/"+sha+"/pulls", cfg.Token, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload []pullRequestPayload
+	if err := json.Unmarshal(resp, &payload); err != nil {
+		return nil, err
+	}
+
+	var pulls []PullRequest
+	for _, p := range payload {
+		if p.MergedAt == "" {
+			continue
+		}
+		pr := PullRequest{
+			Number:   p.Number,
+			Title:    p.Title,
+			URL:      p.HTMLURL,
+			Login:    p.User.Login,
+			MergedAt: p.MergedAt,
+		}
+		for _, label := range p.Labels {
+			pr.Labels = append(pr.Labels, label.Name)
+		}
+		pulls = append(pulls, pr)
+	}
+	return pulls, nil
+}
+
+// IsFirstContribution reports whether login had no pull request merged into
+// the repository before the given RFC 3339 timestamp.
+func IsFirstContribution(cfg *shared.Config, login, before string) (bool, error) {
+	query := "repo:" + cfg.Repo + " type:pr is:merged author:" + login + " merged:<" + before
+	resp, err := request("GET", apiURL+"/search/issues?per_page=1&q="+url.QueryEscape(query), cfg.Token, nil)
+	if err != nil {
+		return false, err
+	}
+
+	var payload struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := json.Unmarshal(resp, &payload); err != nil {
+		return false, err
+	}
+	return payload.TotalCount == 0, nil
+}
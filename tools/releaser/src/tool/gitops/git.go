@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -28,10 +29,24 @@ func CheckUncommittedChanges(cfg *shared.Config) error {
 		output.Warn("Failed to check for uncommitted changes")
 		return err
 	}
-	if strings.TrimSpace(out) != "" {
+	pending := make(map[string]bool)
+	for _, file := range cfg.Journal.PendingFiles() {
+		pending[file] = true
+	}
+	var changes []string
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if len(line) > 3 && pending[line[3:]] {
+			output.Verbose("Keeping " + line[3:] + " changed by the interrupted release")
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			changes = append(changes, line)
+		}
+	}
+	if len(changes) > 0 {
 		output.ReplaceLastLine(label + " ⚠")
 		output.Warn("⚠️  There are uncommitted changes in your working directory:")
-		fmt.Print(out)
+		fmt.Println(strings.Join(changes, "\n"))
 		return errors.New("uncommitted changes")
 	}
 	output.ReplaceLastLine(label + " ✔")
@@ -83,6 +98,53 @@ func Run(dir string, args ...string) (string, error) {
 	return string(out), nil
 }
 
+// Head returns the commit SHA checked out in dir.
+func Head(dir string) (string, error) {
+	out, err := Run(dir, "rev-parse", "HEAD")
+	return strings.TrimSpace(out), err
+}
+
+// Identity returns the git committer of dir as "Name <email>".
+func Identity(dir string) (string, error) {
+	out, err := Run(dir, "var", "GIT_COMMITTER_IDENT")
+	if err != nil {
+		return "", err
+	}
+	ident := strings.TrimSpace(out)
+	if end := strings.LastIndex(ident, ">"); end >= 0 {
+		ident = ident[:end+1]
+	}
+	return ident, nil
+}
+
+// GitDir returns the absolute path of the .git directory of dir.
+func GitDir(dir string) (string, error) {
+	out, err := Run(dir, "rev-parse", "--absolute-git-dir")
+	return strings.TrimSpace(out), err
+}
+
+// Mutate runs a git command changing the repository or its remote, or
+// records it in the plan during a dry run.
+func Mutate(cfg *shared.Config, args ...string) error {
+	if cfg.Plan != nil {
+		cfg.Plan.Git(args)
+		return nil
+	}
+	_, err := Run(cfg.BaseDir, args...)
+	return err
+}
+
+// FetchTags updates the local tags from origin. A dry run leaves the
+// repository untouched and works from the tags already fetched.
+func FetchTags(cfg *shared.Config) error {
+	if cfg.Plan != nil {
+		output.Verbose("Dry run: not fetching tags from origin; using the local tags")
+		return nil
+	}
+	_, err := Run(cfg.BaseDir, "fetch", "--tags")
+	return err
+}
+
 func TagExists(dir, tag string) (bool, error) {
 	cmd := exec.Command("git", "-C", dir, "rev-parse", "-q", "--verify", "refs/tags/"+tag)
 	out, err := cmd.CombinedOutput()
@@ -102,6 +164,95 @@ func TagExists(dir, tag string) (bool, error) {
 	return false, fmt.Errorf("failed to check local tag %s: %w: %s", tag, err, outputText)
 }
 
+// FileAtRef returns the contents of path at ref. The boolean is false when the
+// path does not exist at that ref.
+func FileAtRef(dir, ref, path string) (string, bool, error) {
+	output.VeryVerbose("git -C " + dir + " show " + ref + ":" + path)
+	cmd := exec.Command("git", "-C", dir, "show", ref+":"+path)
+	// The missing path is recognized from the untranslated message.
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	out, err := cmd.Output()
+	if err == nil {
+		return string(out), true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return "", false, fmt.Errorf("failed to read %s at %s: %w", path, ref, err)
+	}
+	// Exit code 128 also covers unknown refs and corrupt objects; only a
+	// missing path means the file did not exist at ref.
+	stderr := strings.TrimSpace(string(exitErr.Stderr))
+	if strings.Contains(stderr, "does not exist in") || strings.Contains(stderr, "exists on disk, but not in") {
+		return "", false, nil
+	}
+	return "", false, fmt.Errorf("failed to read %s at %s: %w: %s", path, ref, err, stderr)
+}
+
+// ListFilesAtRef returns the repository-relative paths of the entries directly
+// inside dir at ref. Use "." for the repository root.
+func ListFilesAtRef(dir, ref, path string) ([]string, error) {
+	args := []string{"ls-tree", "--full-tree", "--name-only", ref}
+	if path != "" && path != "." {
+		args = append(args, "--", strings.TrimSuffix(path, "/")+"/")
+	}
+	out, err := Run(dir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// HasChanges reports whether any file below paths changed between from and
+// HEAD.
+func HasChanges(dir, from string, paths ...string) (bool, error) {
+	out, err := Run(dir, append([]string{"diff", "--name-only", from + "..HEAD", "--"}, paths...)...)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+// MergedTags lists the tags reachable from HEAD that start with prefix.
+func MergedTags(dir, prefix string) ([]string, error) {
+	out, err := Run(dir, "tag", "--merged", "HEAD", "--list", prefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, line := range strings.Split(out, "\n") {
+		if tag := strings.TrimSpace(line); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// MergedCommits lists the commits a merge commit brought in from its second
+// parent. It returns nothing for a commit that is not a merge.
+func MergedCommits(dir, hash string) ([]string, error) {
+	out, err := Run(dir, "rev-list", "--parents", "-n", "1", hash)
+	if err != nil {
+		return nil, err
+	}
+	parents := strings.Fields(out)
+	if len(parents) < 3 {
+		return nil, nil
+	}
+	out, err = Run(dir, "rev-list", parents[1]+".."+parents[2])
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
 func RemoteTagExists(dir, tag string) (bool, error) {
 	out, err := Run(dir, "ls-remote", "--tags", "origin", "refs/tags/"+tag)
 	if err != nil {
@@ -146,3 +297,52 @@ func AheadBehind(dir string) (ahead int, behind int, hasUpstream bool, err error
 
 	return ahead, behind, true, nil
 }
+
+// Commit is one entry of the commit log.
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    string
+	Subject string
+	Body    string
+}
+
+// ShortHash returns the abbreviated commit hash.
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Commits returns the commits in revRange (for example "v1.2.0..HEAD"),
+// newest first. When paths are given, only commits touching them are
+// listed.
+func Commits(dir, revRange string, paths ...string) ([]Commit, error) {
+	args := []string{"log", revRange, "--pretty=format:%H%x1f%an%x1f%aI%x1f%s%x1f%b%x1e"}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	out, err := Run(dir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x1f", 5)
+		if len(fields) < 4 {
+			continue
+		}
+		commit := Commit{Hash: fields[0], Author: fields[1], Date: fields[2], Subject: fields[3]}
+		if len(fields) == 5 {
+			commit.Body = strings.TrimSpace(fields[4])
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
@@ -0,0 +1,126 @@
+package gitops
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "test"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", "a.txt"}, {"commit", "-qm", "init"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestFileAtRef(t *testing.T) {
+	dir := initRepo(t)
+
+	content, found, err := FileAtRef(dir, "HEAD", "a.txt")
+	if err != nil || !found || content != "a\n" {
+		t.Fatalf("expected a.txt at HEAD, got %q, %v, %v", content, found, err)
+	}
+
+	if _, found, err := FileAtRef(dir, "HEAD", "missing.txt"); err != nil || found {
+		t.Fatalf("expected missing.txt to be reported absent, got %v, %v", found, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, err := FileAtRef(dir, "HEAD", "new.txt"); err != nil || found {
+		t.Fatalf("expected an untracked file to be reported absent, got %v, %v", found, err)
+	}
+
+	if _, _, err := FileAtRef(dir, "no-such-ref", "a.txt"); err == nil {
+		t.Fatalf("expected an unknown ref to be an error")
+	}
+}
+
+func TestHasChanges(t *testing.T) {
+	dir := initRepo(t)
+	for _, args := range [][]string{{"tag", "billing-v1.0.0"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "packages", "search"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "packages", "search", "index.js"), []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", "."}, {"commit", "-qm", "search"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	cases := []struct {
+		path string
+		want bool
+	}{
+		{path: "packages/billing", want: false},
+		{path: "packages/search", want: true},
+	}
+	for _, c := range cases {
+		got, err := HasChanges(dir, "billing-v1.0.0", c.path)
+		if err != nil {
+			t.Fatalf("HasChanges(%s) returned error: %v", c.path, err)
+		}
+		if got != c.want {
+			t.Errorf("HasChanges(%s) = %v, want %v", c.path, got, c.want)
+		}
+	}
+}
+
+func TestMergedCommits(t *testing.T) {
+	dir := initRepo(t)
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	base := git("symbolic-ref", "--short", "HEAD")
+	git("checkout", "-qb", "feature")
+	git("commit", "-q", "--allow-empty", "-m", "one")
+	one := git("rev-parse", "HEAD")
+	git("commit", "-q", "--allow-empty", "-m", "two")
+	two := git("rev-parse", "HEAD")
+	git("checkout", "-q", base)
+	git("merge", "-q", "--no-ff", "-m", "Merge pull request #1", "feature")
+	merge := git("rev-parse", "HEAD")
+
+	got, err := MergedCommits(dir, merge)
+	if err != nil {
+		t.Fatalf("MergedCommits returned error: %v", err)
+	}
+	if want := []string{two, one}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got, err := MergedCommits(dir, one); err != nil || got != nil {
+		t.Fatalf("expected nothing for a regular commit, got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,168 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Journal tracks the steps completed by unfinished releases in
+// .git/releaser/state.json, so a failed release resumes where it stopped
+// instead of bumping to a new version. A nil Journal records nothing.
+type Journal struct {
+	path     string
+	Releases []*Entry `json:"releases"`
+}
+
+// Entry is the progress of one release, keyed by component.
+type Entry struct {
+	Component    string   `json:"component,omitempty"`
+	Type         string   `json:"type"`
+	TagPrefix    string   `json:"tag_prefix"`
+	OldTag       string   `json:"old_tag"`
+	OldVersion   string   `json:"old_version"`
+	NewTag       string   `json:"new_tag"`
+	NewVersion   string   `json:"new_version"`
+	Prerelease   bool     `json:"prerelease"`
+	Changes      string   `json:"changes"`
+	ReleaseFiles []string `json:"release_files,omitempty"`
+	// Head is the commit checked out after the last completed step; the
+	// entry is stale once HEAD moves elsewhere.
+	Head      string   `json:"head"`
+	Steps     []string `json:"steps"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// Load reads the journal kept in gitDir. A missing file is an empty journal.
+func Load(gitDir string) (*Journal, error) {
+	j := &Journal{path: filepath.Join(gitDir, "releaser", "state.json")}
+	b, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %w", j.path, err)
+	}
+	if err := json.Unmarshal(b, j); err != nil {
+		return nil, fmt.Errorf("Failed to parse %s: %w", j.path, err)
+	}
+	return j, nil
+}
+
+// Path returns the location of the journal file.
+func (j *Journal) Path() string {
+	if j == nil {
+		return ""
+	}
+	return j.path
+}
+
+// Find returns the unfinished release of component, if any.
+func (j *Journal) Find(component string) *Entry {
+	if j == nil {
+		return nil
+	}
+	for _, e := range j.Releases {
+		if e.Component == component {
+			return e
+		}
+	}
+	return nil
+}
+
+// Start begins a new entry for component, replacing any previous one.
+func (j *Journal) Start(component string) *Entry {
+	e := &Entry{Component: component}
+	if j == nil {
+		return e
+	}
+	j.remove(component)
+	j.Releases = append(j.Releases, e)
+	return e
+}
+
+// Finish forgets the release of component.
+func (j *Journal) Finish(component string) error {
+	if j == nil {
+		return nil
+	}
+	j.remove(component)
+	return j.Save()
+}
+
+// PendingFiles lists the files written by unfinished releases that were not
+// committed yet; they may be left in the working tree by a failure.
+func (j *Journal) PendingFiles() []string {
+	if j == nil {
+		return nil
+	}
+	var files []string
+	for _, e := range j.Releases {
+		if !e.Done("CommitRelease") {
+			files = append(files, e.ReleaseFiles...)
+		}
+	}
+	return files
+}
+
+// Save writes the journal, removing the file once no release is pending.
+func (j *Journal) Save() error {
+	if j == nil {
+		return nil
+	}
+	if len(j.Releases) == 0 {
+		if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	b, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(j.path, append(b, '\n'), 0o644)
+}
+
+func (j *Journal) remove(component string) {
+	kept := j.Releases[:0]
+	for _, e := range j.Releases {
+		if e.Component != component {
+			kept = append(kept, e)
+		}
+	}
+	j.Releases = kept
+}
+
+// Done reports whether step was completed.
+func (e *Entry) Done(step string) bool {
+	if e == nil {
+		return false
+	}
+	for _, s := range e.Steps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete marks step as completed at head.
+func (e *Entry) Complete(step, head string) {
+	e.Steps = append(e.Steps, step)
+	e.Head = head
+	e.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+}
+
+// LastStep returns the most recently completed step.
+func (e *Entry) LastStep() string {
+	if len(e.Steps) == 0 {
+		return ""
+	}
+	return e.Steps[len(e.Steps)-1]
+}
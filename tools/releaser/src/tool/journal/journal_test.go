@@ -0,0 +1,87 @@
+package journal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestJournal_RoundTrip(t *testing.T) {
+	gitDir := t.TempDir()
+	j, err := Load(gitDir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	e := j.Start("billing")
+	e.NewTag = "billing-v1.1.0"
+	e.ReleaseFiles = []string{"billing/CHANGELOG.md"}
+	e.Complete("BuildChanges", "abc123")
+	if err := j.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	j, err = Load(gitDir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	got := j.Find("billing")
+	if got == nil || got.NewTag != "billing-v1.1.0" || got.Head != "abc123" || !got.Done("BuildChanges") || got.Done("CreateTag") {
+		t.Fatalf("unexpected entry %+v", got)
+	}
+	if j.Find("") != nil {
+		t.Fatalf("expected no entry for the repository release")
+	}
+	if files := j.PendingFiles(); !reflect.DeepEqual(files, []string{"billing/CHANGELOG.md"}) {
+		t.Fatalf("unexpected pending files %v", files)
+	}
+
+	got.Complete("CommitRelease", "def456")
+	if files := j.PendingFiles(); len(files) != 0 {
+		t.Fatalf("expected committed files not to be pending, got %v", files)
+	}
+
+	if err := j.Finish("billing"); err != nil {
+		t.Fatalf("Finish returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "releaser", "state.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected the journal file to be removed, got %v", err)
+	}
+}
+
+func TestJournal_NilRecordsNothing(t *testing.T) {
+	var j *Journal
+	e := j.Start("")
+	e.Complete("VersionBump", "abc123")
+	if j.Find("") != nil || j.PendingFiles() != nil {
+		t.Fatalf("expected a nil journal to stay empty")
+	}
+	if err := j.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+}
+
+func TestJournal_RecordRollback(t *testing.T) {
+	gitDir := t.TempDir()
+	j, err := Load(gitDir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	for _, tag := range []string{"v1.1.0", "v1.0.0"} {
+		if err := j.RecordRollback(Rollback{Tag: tag, RemoteTag: true, LocalTag: true}); err != nil {
+			t.Fatalf("RecordRollback returned error: %v", err)
+		}
+	}
+	b, err := os.ReadFile(filepath.Join(gitDir, "releaser", "rollbacks.json"))
+	if err != nil {
+		t.Fatalf("expected rollbacks.json to be written: %v", err)
+	}
+	var rollbacks []Rollback
+	if err := json.Unmarshal(b, &rollbacks); err != nil {
+		t.Fatalf("failed to parse rollbacks.json: %v", err)
+	}
+	if len(rollbacks) != 2 || rollbacks[0].Tag != "v1.1.0" || rollbacks[1].Tag != "v1.0.0" || rollbacks[1].RolledBackAt == "" {
+		t.Fatalf("unexpected rollbacks %+v", rollbacks)
+	}
+}
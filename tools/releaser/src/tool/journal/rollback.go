@@ -0,0 +1,52 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Rollback records what `releaser rollback` removed.
+type Rollback struct {
+	Tag       string `json:"tag"`
+	Component string `json:"component,omitempty"`
+	// Release is the URL of the deleted GitHub release.
+	Release   string `json:"release,omitempty"`
+	RemoteTag bool   `json:"remote_tag"`
+	LocalTag  bool   `json:"local_tag"`
+	// Reverted is the release commit that was reverted.
+	Reverted     string `json:"reverted,omitempty"`
+	RolledBackAt string `json:"rolled_back_at"`
+}
+
+// RecordRollback appends r to rollbacks.json next to the journal.
+func (j *Journal) RecordRollback(r Rollback) error {
+	if j == nil {
+		return nil
+	}
+	path := filepath.Join(filepath.Dir(j.path), "rollbacks.json")
+	var rollbacks []Rollback
+	b, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(b, &rollbacks); err != nil {
+			return fmt.Errorf("Failed to parse %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("Failed to read %s: %w", path, err)
+	}
+
+	r.RolledBackAt = time.Now().UTC().Format(time.RFC3339)
+	rollbacks = append(rollbacks, r)
+	b, err = json.MarshalIndent(rollbacks, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
@@ -0,0 +1,93 @@
+package monorepo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"releaser/tool/output"
+	"releaser/tool/shared"
+	"releaser/tool/version"
+)
+
+// dependencySections are the manifest sections holding requirements on
+// other components.
+var dependencySections = []struct {
+	file     string
+	sections []string
+}{
+	{file: "composer.json", sections: []string{"require", "require-dev"}},
+	{file: "package.json", sections: []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}},
+}
+
+// simpleConstraint matches the constraints that can be raised safely: an
+// optional ^, ~, >= or = operator followed by a single version.
+var simpleConstraint = regexp.MustCompile(`^(\^|~|>=|=)?\s*v?\d+(?:\.\d+){0,2}(?:-[0-9A-Za-z.-]+)?$`)
+
+// UpdateConstraints raises the requirements of the component being released
+// on the components released before it in the same run. The edited
+// manifests join the release commit.
+func UpdateConstraints(cfg *shared.Config) error {
+	if len(cfg.DependencyBumps) == 0 {
+		return nil
+	}
+	packages := make([]string, 0, len(cfg.DependencyBumps))
+	for name := range cfg.DependencyBumps {
+		packages = append(packages, name)
+	}
+	sort.Strings(packages)
+
+	for _, manifest := range dependencySections {
+		file := cfg.RepoPath(manifest.file)
+		path := filepath.Join(cfg.BaseDir, file)
+		b, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to read %s: %w", path, err)
+		}
+
+		src := string(b)
+		changed := false
+		for _, name := range packages {
+			for _, section := range manifest.sections {
+				start, end, ok := version.JSONStringRange(src, section, name)
+				if !ok {
+					continue
+				}
+				next, ok := raiseConstraint(src[start:end], cfg.DependencyBumps[name])
+				if !ok {
+					output.Verbose("Keeping constraint " + src[start:end] + " on " + name + " in " + file)
+					continue
+				}
+				output.Info("Requiring " + name + " " + next + " in " + file)
+				src = src[:start] + next + src[end:]
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := cfg.Plan.WriteFile(cfg.BaseDir, file, []byte(src)); err != nil {
+			return err
+		}
+		cfg.ReleaseFiles = append(cfg.ReleaseFiles, file)
+	}
+	return nil
+}
+
+// raiseConstraint keeps the operator of a simple constraint and points it at
+// the released version. Ranges, wildcards, branch and workspace references
+// are left alone.
+func raiseConstraint(constraint, released string) (string, bool) {
+	m := simpleConstraint.FindStringSubmatch(constraint)
+	if m == nil {
+		return "", false
+	}
+	next := m[1] + released
+	return next, next != constraint
+}
@@ -0,0 +1,217 @@
+package monorepo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"releaser/tool/composer"
+	"releaser/tool/shared"
+)
+
+// Graph links the components of a monorepo that require each other through
+// their composer.json or package.json, as with composer path repositories
+// or npm workspaces.
+type Graph struct {
+	components []shared.Component
+	// packages maps a composer or npm package name to its component.
+	packages map[string]string
+	// requires maps a component to the components it depends on.
+	requires map[string]map[string]bool
+}
+
+type npmManifest struct {
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// Load reads the manifests of the components from the working tree.
+func Load(baseDir string, components []shared.Component) (*Graph, error) {
+	g := &Graph{
+		components: components,
+		packages:   make(map[string]string),
+		requires:   make(map[string]map[string]bool),
+	}
+	required := make(map[string][]string)
+	for _, component := range components {
+		names, deps, err := readManifests(filepath.Join(baseDir, component.Path))
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", component.Name, err)
+		}
+		for _, name := range names {
+			g.packages[name] = component.Name
+		}
+		required[component.Name] = deps
+	}
+	for _, component := range components {
+		g.requires[component.Name] = make(map[string]bool)
+		for _, dep := range required[component.Name] {
+			if owner, ok := g.packages[dep]; ok && owner != component.Name {
+				g.requires[component.Name][owner] = true
+			}
+		}
+	}
+	return g, nil
+}
+
+// readManifests returns the package names a component publishes and the
+// packages it requires.
+func readManifests(dir string) ([]string, []string, error) {
+	var names, deps []string
+
+	b, err := os.ReadFile(filepath.Join(dir, "composer.json"))
+	switch {
+	case err == nil:
+		manifest, err := composer.ParseManifest(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse composer.json: %w", err)
+		}
+		if manifest.Name != "" {
+			names = append(names, manifest.Name)
+		}
+		deps = append(deps, mapKeys(manifest.Require)...)
+		deps = append(deps, mapKeys(manifest.RequireDev)...)
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, nil, err
+	}
+
+	b, err = os.ReadFile(filepath.Join(dir, "package.json"))
+	switch {
+	case err == nil:
+		var manifest npmManifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse package.json: %w", err)
+		}
+		if manifest.Name != "" {
+			names = append(names, manifest.Name)
+		}
+		for _, section := range []map[string]string{manifest.Dependencies, manifest.DevDependencies, manifest.PeerDependencies, manifest.OptionalDependencies} {
+			deps = append(deps, mapKeys(section)...)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, nil, err
+	}
+	return names, deps, nil
+}
+
+// Component returns the configuration of the named component.
+func (g *Graph) Component(name string) (shared.Component, bool) {
+	for _, component := range g.components {
+		if component.Name == name {
+			return component, true
+		}
+	}
+	return shared.Component{}, false
+}
+
+// Packages lists the package names published by a component.
+func (g *Graph) Packages(component string) []string {
+	var names []string
+	for name, owner := range g.packages {
+		if owner == component {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Requires lists the components the named one depends on, in configuration
+// order.
+func (g *Graph) Requires(component string) []string {
+	var deps []string
+	for _, c := range g.components {
+		if g.requires[component][c.Name] {
+			deps = append(deps, c.Name)
+		}
+	}
+	return deps
+}
+
+// WithDependents adds every component depending, directly or not, on one of
+// names.
+func (g *Graph) WithDependents(names []string) []string {
+	selected := make(map[string]bool)
+	for _, name := range names {
+		selected[name] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, c := range g.components {
+			if selected[c.Name] {
+				continue
+			}
+			for dep := range g.requires[c.Name] {
+				if selected[dep] {
+					selected[c.Name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	var out []string
+	for _, c := range g.components {
+		if selected[c.Name] {
+			out = append(out, c.Name)
+		}
+	}
+	return out
+}
+
+// Order sorts names so every component comes after the components it
+// depends on, keeping the configuration order otherwise.
+func (g *Graph) Order(names []string) ([]string, error) {
+	pending := make(map[string]bool)
+	for _, name := range names {
+		pending[name] = true
+	}
+
+	var ordered []string
+	for len(pending) > 0 {
+		progressed := false
+		for _, c := range g.components {
+			if !pending[c.Name] || !g.ready(c.Name, pending) {
+				continue
+			}
+			ordered = append(ordered, c.Name)
+			delete(pending, c.Name)
+			progressed = true
+		}
+		if !progressed {
+			cycle := make([]string, 0, len(pending))
+			for name := range pending {
+				cycle = append(cycle, name)
+			}
+			sort.Strings(cycle)
+			return nil, fmt.Errorf("components %s depend on each other", strings.Join(cycle, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+func (g *Graph) ready(name string, pending map[string]bool) bool {
+	for dep := range g.requires[name] {
+		if pending[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,119 @@
+package monorepo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"releaser/tool/shared"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testGraph(t *testing.T) (*Graph, string) {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "packages/billing/composer.json"), `{"name": "acme/billing", "require": {"acme/support": "^1.2"}}`)
+	writeFile(t, filepath.Join(dir, "packages/support/composer.json"), `{"name": "acme/support", "require": {"php": "^8.2"}}`)
+	writeFile(t, filepath.Join(dir, "packages/dashboard/package.json"), `{"name": "@acme/dashboard", "dependencies": {"@acme/ui": "workspace:*"}}`)
+	writeFile(t, filepath.Join(dir, "packages/ui/package.json"), `{"name": "@acme/ui"}`)
+	writeFile(t, filepath.Join(dir, "packages/shop/composer.json"), `{"name": "acme/shop", "require": {"acme/billing": "^2.0"}}`)
+
+	graph, err := Load(dir, []shared.Component{
+		{Name: "shop", Path: "packages/shop"},
+		{Name: "billing", Path: "packages/billing"},
+		{Name: "support", Path: "packages/support"},
+		{Name: "dashboard", Path: "packages/dashboard"},
+		{Name: "ui", Path: "packages/ui"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return graph, dir
+}
+
+func TestGraph_WithDependentsAndOrder(t *testing.T) {
+	graph, _ := testGraph(t)
+
+	names := graph.WithDependents([]string{"support"})
+	if !reflect.DeepEqual(names, []string{"shop", "billing", "support"}) {
+		t.Fatalf("unexpected dependents %v", names)
+	}
+	order, err := graph.Order(names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(order, []string{"support", "billing", "shop"}) {
+		t.Fatalf("unexpected order %v", order)
+	}
+	if deps := graph.Requires("dashboard"); !reflect.DeepEqual(deps, []string{"ui"}) {
+		t.Fatalf("unexpected npm workspace dependencies %v", deps)
+	}
+}
+
+func TestGraph_OrderReportsCycles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a/composer.json"), `{"name": "acme/a", "require": {"acme/b": "^1.0"}}`)
+	writeFile(t, filepath.Join(dir, "b/composer.json"), `{"name": "acme/b", "require-dev": {"acme/a": "^1.0"}}`)
+	graph, err := Load(dir, []shared.Component{{Name: "a", Path: "a"}, {Name: "b", Path: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := graph.Order([]string{"a", "b"}); err == nil {
+		t.Fatalf("expected a dependency cycle error")
+	}
+}
+
+func TestUpdateConstraints(t *testing.T) {
+	_, dir := testGraph(t)
+	writeFile(t, filepath.Join(dir, "packages/billing/package.json"), `{"name": "billing-ui", "devDependencies": {"@acme/ui": "workspace:*"}}`)
+	cfg := (&shared.Config{BaseDir: dir}).ForComponent(shared.Component{Name: "billing", Path: "packages/billing"})
+	cfg.DependencyBumps = map[string]string{"acme/support": "1.3.0", "@acme/ui": "2.0.0"}
+
+	if err := UpdateConstraints(cfg); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(filepath.Join(dir, "packages/billing/composer.json"))
+	if string(b) != `{"name": "acme/billing", "require": {"acme/support": "^1.3.0"}}` {
+		t.Fatalf("unexpected composer.json %s", b)
+	}
+	b, _ = os.ReadFile(filepath.Join(dir, "packages/billing/package.json"))
+	if string(b) != `{"name": "billing-ui", "devDependencies": {"@acme/ui": "workspace:*"}}` {
+		t.Fatalf("expected workspace constraints to be kept, got %s", b)
+	}
+	if !reflect.DeepEqual(cfg.ReleaseFiles, []string{"packages/billing/composer.json"}) {
+		t.Fatalf("unexpected release files %v", cfg.ReleaseFiles)
+	}
+}
+
+func TestRaiseConstraint(t *testing.T) {
+	for constraint, expected := range map[string]string{
+		"^1.2":     "^1.3.0",
+		"~1.2.0":   "~1.3.0",
+		">=1.0":    ">=1.3.0",
+		"1.2.0":    "1.3.0",
+		"^1 || ^2": "",
+		"*":        "",
+		"dev-main": "",
+	} {
+		got, ok := raiseConstraint(constraint, "1.3.0")
+		if expected == "" {
+			if ok {
+				t.Fatalf("expected %q to be kept, got %q", constraint, got)
+			}
+			continue
+		}
+		if !ok || got != expected {
+			t.Fatalf("%q: expected %q, got %q", constraint, expected, got)
+		}
+	}
+}
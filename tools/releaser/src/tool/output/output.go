@@ -1,7 +1,6 @@
 package output
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"strings"
@@ -29,15 +28,6 @@ func Blank() {
 	fmt.Println()
 }
 
-func Ask(prompt string) string {
-	Blank()
-	fmt.Print(prompt)
-	reader := bufio.NewReader(os.Stdin)
-	answer, _ := reader.ReadString('\n')
-	Blank()
-	return strings.TrimSpace(answer)
-}
-
 func Info(msg string) {
 	printLine(os.Stdout, "INFO", colorCyan, msg)
 }
@@ -0,0 +1,28 @@
+// Package outputtest scripts the prompts of the release flow in tests.
+package outputtest
+
+import (
+	"testing"
+
+	"releaser/tool/output"
+)
+
+// Script answers decisions the way the non-interactive mode answers them
+// from the prompts settings, and records the decisions in the order they
+// were asked.
+type Script struct {
+	Answers map[string]string
+	Asked   []string
+}
+
+func (s *Script) Ask(d output.Decision) (string, error) {
+	s.Asked = append(s.Asked, d.Name)
+	return output.NonInteractive(s.Answers).Ask(d)
+}
+
+// Use installs p as the prompter until the end of the test.
+func Use(t testing.TB, p output.Prompter) {
+	t.Helper()
+	output.SetPrompter(p)
+	t.Cleanup(func() { output.SetPrompter(output.Terminal{}) })
+}
@@ -0,0 +1,89 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Decision is a question asked to the user.
+type Decision struct {
+	// Name identifies the decision in the prompts settings and in errors.
+	Name     string
+	Question string
+	// Default is the answer given by an empty reply.
+	Default string
+}
+
+// Prompter answers the decisions of a release.
+type Prompter interface {
+	Ask(d Decision) (string, error)
+}
+
+// MissingDecisionError is returned when a decision has no answer in
+// non-interactive mode.
+type MissingDecisionError struct {
+	Decision string
+}
+
+func (e *MissingDecisionError) Error() string {
+	return "Cannot prompt for " + e.Decision + " in non-interactive mode"
+}
+
+var prompter Prompter = Terminal{}
+
+// SetPrompter replaces the prompter used by Prompt.
+func SetPrompter(p Prompter) {
+	prompter = p
+}
+
+// Prompt asks a decision through the current prompter.
+func Prompt(d Decision) (string, error) {
+	return prompter.Ask(d)
+}
+
+// Terminal asks on stdin.
+type Terminal struct{}
+
+func (Terminal) Ask(d Decision) (string, error) {
+	Blank()
+	fmt.Print(d.Question)
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	Blank()
+	if answer = strings.TrimSpace(answer); answer != "" {
+		return answer, nil
+	}
+	return d.Default, nil
+}
+
+// NonInteractive answers from the configured answers, keyed by decision
+// name; "default" takes the default of the decision. Unanswered decisions
+// fail with a MissingDecisionError.
+type NonInteractive map[string]string
+
+func (n NonInteractive) Ask(d Decision) (string, error) {
+	answer, ok := n[d.Name]
+	if !ok {
+		return "", &MissingDecisionError{Decision: d.Name}
+	}
+	if answer == "default" {
+		answer = d.Default
+	}
+	Info("Answering " + d.Name + " with " + answer + " (non-interactive)")
+	return answer, nil
+}
+
+// StdinIsTerminal reports whether stdin is attached to a terminal. Like a
+// terminal, /dev/null is a character device, so it is ruled out explicitly.
+func StdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, null) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,24 @@
+package output
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNonInteractive_MissingDecision(t *testing.T) {
+	_, err := NonInteractive{}.Ask(Decision{Name: "confirm_release", Default: "y"})
+	var missing *MissingDecisionError
+	if !errors.As(err, &missing) || missing.Decision != "confirm_release" {
+		t.Fatalf("expected a MissingDecisionError for confirm_release, got %v", err)
+	}
+}
+
+func TestNonInteractive_ConfiguredAnswers(t *testing.T) {
+	answers := NonInteractive{"release_type": "default", "confirm_release": "no"}
+	if got, err := answers.Ask(Decision{Name: "release_type", Default: "minor"}); err != nil || got != "minor" {
+		t.Fatalf("expected the default answer minor, got %q, %v", got, err)
+	}
+	if got, err := answers.Ask(Decision{Name: "confirm_release", Default: "y"}); err != nil || got != "no" {
+		t.Fatalf("expected the configured answer no, got %q, %v", got, err)
+	}
+}
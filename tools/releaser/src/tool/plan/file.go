@@ -0,0 +1,111 @@
+package plan
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileFormat is the version of the plan file layout.
+const FileFormat = 1
+
+// KeyEnv names the environment variable holding the secret plan files are
+// signed with. The plan and apply sides must share it.
+const KeyEnv = "RELEASER_PLAN_KEY"
+
+// File is a reviewed release plan, written by `releaser plan` and executed
+// by `releaser apply`. The signature is an HMAC of every other field keyed
+// by KeyEnv, so a plan edited without the key is rejected.
+type File struct {
+	Format    int    `json:"format"`
+	Repo      string `json:"repo"`
+	BaseSHA   string `json:"base_sha"`
+	CreatedAt string `json:"created_at"`
+	// SignedBy is the git identity of whoever made the plan.
+	SignedBy  string  `json:"signed_by"`
+	Releases  []*Plan `json:"releases"`
+	Signature string  `json:"signature"`
+}
+
+// NewFile bundles the plans of a run made on baseSHA by signedBy.
+func NewFile(repo, baseSHA, signedBy string, releases []*Plan) *File {
+	return &File{
+		Format:    FileFormat,
+		Repo:      repo,
+		BaseSHA:   baseSHA,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		SignedBy:  signedBy,
+		Releases:  releases,
+	}
+}
+
+// Write stores the plan file signed with key.
+func (f *File) Write(path string, key []byte) error {
+	if len(key) == 0 {
+		return fmt.Errorf("%s is required to sign the plan", KeyEnv)
+	}
+	sig, err := f.sign(key)
+	if err != nil {
+		return err
+	}
+	f.Signature = sig
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("Failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadFile loads a plan file and verifies its signature with key.
+func ReadFile(path string, key []byte) (*File, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%s is required to verify the plan", KeyEnv)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %w", path, err)
+	}
+	var f File
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("Failed to parse %s: %w", path, err)
+	}
+	if f.Format != FileFormat {
+		return nil, fmt.Errorf("Unsupported plan format %d in %s (expected %d)", f.Format, path, FileFormat)
+	}
+	sig, err := f.sign(key)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(f.Signature), []byte(sig)) {
+		return nil, fmt.Errorf("Invalid signature in %s; the plan was modified after it was signed off, or %s differs from the one it was signed with", path, KeyEnv)
+	}
+	if len(f.Releases) == 0 {
+		return nil, errors.New("No release in " + path)
+	}
+	return &f, nil
+}
+
+// sign computes the HMAC of the plan file without its signature.
+func (f *File) sign(key []byte) (string, error) {
+	unsigned := *f
+	unsigned.Signature = ""
+	b, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,58 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testKey = []byte("s3cret")
+
+func TestFile_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "releaser", "plan.json")
+	f := NewFile("acme/app", "abc123", "Ada <ada@example.com>", []*Plan{{Type: "minor", OldTag: "v1.0.0", NewTag: "v1.1.0", Notes: "## What's Changed"}})
+	if err := f.Write(path, testKey); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	got, err := ReadFile(path, testKey)
+	if err != nil {
+		t.Fatalf("ReadFile returned error: %v", err)
+	}
+	if got.BaseSHA != "abc123" || got.SignedBy != "Ada <ada@example.com>" || len(got.Releases) != 1 || got.Releases[0].NewTag != "v1.1.0" {
+		t.Fatalf("unexpected plan %+v", got)
+	}
+}
+
+func TestReadFile_RejectsEditedPlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	f := NewFile("acme/app", "abc123", "Ada <ada@example.com>", []*Plan{{Type: "minor", NewTag: "v1.1.0"}})
+	if err := f.Write(path, testKey); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(b), "v1.1.0", "v2.0.0", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(path, testKey); err == nil || !strings.Contains(err.Error(), "Invalid signature") {
+		t.Fatalf("expected an invalid signature, got %v", err)
+	}
+}
+
+func TestReadFile_RejectsPlanResignedWithoutKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	f := NewFile("acme/app", "abc123", "Ada <ada@example.com>", []*Plan{{Type: "minor", NewTag: "v1.1.0"}})
+	f.Releases[0].NewTag = "v2.0.0"
+	if err := f.Write(path, []byte("guessed")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if _, err := ReadFile(path, testKey); err == nil || !strings.Contains(err.Error(), "Invalid signature") {
+		t.Fatalf("expected a plan signed with another key to be rejected, got %v", err)
+	}
+	if _, err := ReadFile(path, nil); err == nil || !strings.Contains(err.Error(), KeyEnv) {
+		t.Fatalf("expected a missing key to be reported, got %v", err)
+	}
+}
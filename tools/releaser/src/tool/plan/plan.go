@@ -0,0 +1,165 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"releaser/tool/output"
+)
+
+// Action kinds.
+const (
+	KindWrite   = "write"
+	KindGit     = "git"
+	KindRequest = "request"
+)
+
+// Plan is what a release would do. During a dry run the steps record their
+// mutations here instead of performing them.
+type Plan struct {
+	Component  string `json:"component,omitempty"`
+	Type       string `json:"type"`
+	OldTag     string `json:"old_tag"`
+	OldVersion string `json:"old_version"`
+	NewTag     string `json:"new_tag"`
+	NewVersion string `json:"new_version"`
+	Prerelease bool   `json:"prerelease"`
+	// Notes is the body of the GitHub release.
+	Notes string `json:"notes"`
+	// Rules are the findings behind the detected release type.
+	Rules   []Rule   `json:"rules,omitempty"`
+	Actions []Action `json:"actions"`
+}
+
+// Rule is one finding of the release type detection.
+type Rule struct {
+	File     string `json:"file"`
+	Severity string `json:"severity"`
+	Reason   string `json:"reason"`
+}
+
+// Action is one recorded mutation.
+type Action struct {
+	Kind string `json:"kind"`
+	// Path is the written file, relative to the repository root.
+	Path    string `json:"path,omitempty"`
+	Content string `json:"content,omitempty"`
+	// Args are the arguments of the git command.
+	Args []string `json:"args,omitempty"`
+	// Details lists what the action carries along, such as the commits a
+	// push would publish.
+	Details []string `json:"details,omitempty"`
+	// Method, URL and Payload describe the GitHub API request.
+	Method  string          `json:"method,omitempty"`
+	URL     string          `json:"url,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// WriteFile writes content to file, relative to baseDir, or records the
+// write when p is a dry run plan.
+func (p *Plan) WriteFile(baseDir, file string, content []byte) error {
+	if p == nil {
+		path := filepath.Join(baseDir, file)
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return fmt.Errorf("Failed to write %s: %w", path, err)
+		}
+		return nil
+	}
+	p.Actions = append(p.Actions, Action{Kind: KindWrite, Path: file, Content: string(content)})
+	return nil
+}
+
+// Git records a git command changing the repository or its remote.
+func (p *Plan) Git(args []string, details ...string) {
+	p.Actions = append(p.Actions, Action{Kind: KindGit, Args: args, Details: details})
+}
+
+// Request records a GitHub API request, such as the release creation.
+func (p *Plan) Request(method, url string, payload []byte) {
+	p.Actions = append(p.Actions, Action{Kind: KindRequest, Method: method, URL: url, Payload: payload})
+}
+
+// Commits lists the messages of the commits recorded by the plan. They
+// count as ahead of upstream, since a dry run never creates them. A nil
+// plan records no commit.
+func (p *Plan) Commits() []string {
+	if p == nil {
+		return nil
+	}
+	var messages []string
+	for _, action := range p.Actions {
+		if action.Kind != KindGit || len(action.Args) == 0 || action.Args[0] != "commit" {
+			continue
+		}
+		message := ""
+		for i, arg := range action.Args {
+			if arg == "-m" && i+1 < len(action.Args) {
+				message = action.Args[i+1]
+				break
+			}
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// Print shows the plan.
+func (p *Plan) Print() {
+	title := "Dry run: release plan"
+	if p.Component != "" {
+		title += " for component " + p.Component
+	}
+	output.Info(title)
+	output.Continue("Release type:    " + p.Type)
+	output.Continue("Current version: " + p.OldVersion + " (" + p.OldTag + ")")
+	output.Continue("New version:     " + p.NewVersion + " (" + p.NewTag + ")")
+	output.Continue("Tag to create:   " + p.NewTag)
+	if p.Prerelease {
+		output.Continue("Pre-release:     yes")
+	}
+	p.PrintActions()
+}
+
+// PrintActions lists the recorded actions.
+func (p *Plan) PrintActions() {
+	for i, action := range p.Actions {
+		output.Continue(fmt.Sprintf("%d. %s", i+1, action.describe()))
+		for _, detail := range action.Details {
+			output.Continue("     " + detail)
+		}
+		if len(action.Payload) > 0 {
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, action.Payload, "", "  "); err != nil {
+				indented.Write(action.Payload)
+			}
+			for _, line := range strings.Split(indented.String(), "\n") {
+				output.Continue("     " + line)
+			}
+		}
+	}
+}
+
+func (a Action) describe() string {
+	switch a.Kind {
+	case KindWrite:
+		return fmt.Sprintf("write %s (%d lines)", a.Path, strings.Count(a.Content, "\n"))
+	case KindGit:
+		args := make([]string, len(a.Args))
+		for i, arg := range a.Args {
+			args[i] = arg
+			if strings.ContainsAny(arg, " \t\"'") {
+				args[i] = strconv.Quote(arg)
+			}
+		}
+		return "git " + strings.Join(args, " ")
+	case KindRequest:
+		return a.Method + " " + a.URL
+	default:
+		return a.Kind
+	}
+}
@@ -0,0 +1,69 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteFile_NilPlanWrites(t *testing.T) {
+	dir := t.TempDir()
+	var p *Plan
+	if err := p.WriteFile(dir, "CHANGELOG.md", []byte("# Changelog\n")); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+	if err != nil {
+		t.Fatalf("expected file to be written: %v", err)
+	}
+	if string(b) != "# Changelog\n" {
+		t.Fatalf("unexpected content %q", b)
+	}
+}
+
+func TestWriteFile_DryRunRecords(t *testing.T) {
+	dir := t.TempDir()
+	p := &Plan{}
+	if err := p.WriteFile(dir, "CHANGELOG.md", []byte("# Changelog\n")); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "CHANGELOG.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected no file to be written, got %v", err)
+	}
+	if len(p.Actions) != 1 || p.Actions[0].Kind != KindWrite || p.Actions[0].Path != "CHANGELOG.md" {
+		t.Fatalf("unexpected actions %+v", p.Actions)
+	}
+}
+
+func TestActionDescribe(t *testing.T) {
+	cases := []struct {
+		action Action
+		want   string
+	}{
+		{Action{Kind: KindGit, Args: []string{"commit", "-m", "Release v1.2.0"}}, `git commit -m "Release v1.2.0"`},
+		{Action{Kind: KindGit, Args: []string{"push", "origin", "v1.2.0"}}, "git push origin v1.2.0"},
+		{Action{Kind: KindWrite, Path: "package.json", Content: "{\n}\n"}, "write package.json (2 lines)"},
+		{Action{Kind: KindRequest, Method: "POST", URL: "https://api.github.com/repos/acme/app/releases"}, "POST https://api.github.com/repos/acme/app/releases"},
+	}
+	for _, c := range cases {
+		if got := c.action.describe(); got != c.want {
+			t.Errorf("describe() = %q, want %q", got, c.want)
+		}
+	}
+}
+
+func TestCommits(t *testing.T) {
+	var none *Plan
+	if got := none.Commits(); got != nil {
+		t.Fatalf("expected no commits for a nil plan, got %v", got)
+	}
+	p := &Plan{}
+	p.Git([]string{"add", "--", "CHANGELOG.md"})
+	p.Git([]string{"commit", "-m", "Release billing-v1.2.0"})
+	p.Git([]string{"commit", "-m", "Release search-v2.0.0"})
+	p.Git([]string{"push"})
+	if got, want := p.Commits(), []string{"Release billing-v1.2.0", "Release search-v2.0.0"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,258 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"releaser/tool/output"
+	"releaser/tool/shared"
+)
+
+const defaultChangelogPath = "CHANGELOG.md"
+
+const changelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+`
+
+var changelogLinkPattern = regexp.MustCompile(`^\[([^\]]+)\]:\s*\S+`)
+
+// changelogRelease is the version section added to the changelog.
+type changelogRelease struct {
+	Version        string
+	Date           string
+	Sections       []Section
+	VersionLink    string
+	UnreleasedLink string
+}
+
+// updateChangelog adds the release to the Keep a Changelog file of the
+// repository. Without a configured path the file is only updated when
+// CHANGELOG.md already exists.
+func updateChangelog(cfg *shared.Config, notes Notes) error {
+	name := cfg.Project.Changelog.Path
+	if name == "" {
+		name = defaultChangelogPath
+	}
+	name = cfg.RepoPath(name)
+	path := filepath.Join(cfg.BaseDir, name)
+
+	content, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && cfg.Project.Changelog.Path == "":
+		output.Verbose("No " + name + " found; skipping changelog update")
+		return nil
+	case errors.Is(err, os.ErrNotExist):
+		content = []byte(changelogHeader)
+	case err != nil:
+		return fmt.Errorf("Failed to read %s: %w", path, err)
+	}
+
+	if strings.Contains(string(content), "## ["+cfg.NewVer+"]") {
+		output.Info(name + " already lists " + cfg.NewVer + "; skipping changelog update.")
+		return nil
+	}
+
+	repoURL := "https://github.com/" + cfg.Repo
+	updated := insertChangelogRelease(string(content), changelogRelease{
+		Version:        cfg.NewVer,
+		Date:           time.Now().Format("2006-01-02"),
+		Sections:       notes.Sections,
+		VersionLink:    repoURL + "/compare/" + cfg.OldTag + "..." + cfg.NewTag,
+		UnreleasedLink: repoURL + "/compare/" + cfg.NewTag + "...HEAD",
+	})
+
+	output.Info("Updating " + name + "...")
+	if err := cfg.Plan.WriteFile(cfg.BaseDir, name, []byte(updated)); err != nil {
+		return err
+	}
+	cfg.ReleaseFiles = append(cfg.ReleaseFiles, name)
+	return nil
+}
+
+// insertChangelogRelease adds a version section below `## [Unreleased]`,
+// moving the unreleased entries into it, and updates the compare links at
+// the bottom of the file.
+func insertChangelogRelease(content string, rel changelogRelease) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	unreleased, end := -1, -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if unreleased >= 0 {
+			end = i
+			break
+		}
+		if strings.EqualFold(strings.TrimSpace(line), "## [Unreleased]") {
+			unreleased = i
+		}
+	}
+
+	var head, pending, tail []string
+	switch {
+	case unreleased >= 0 && end < 0:
+		head = lines[:unreleased+1]
+		pending, tail = splitChangelogLinks(lines[unreleased+1:])
+	case unreleased >= 0:
+		head = lines[:unreleased+1]
+		pending = lines[unreleased+1 : end]
+		tail = lines[end:]
+	default:
+		insertAt := len(lines)
+		for i, line := range lines {
+			if strings.HasPrefix(line, "## ") {
+				insertAt = i
+				break
+			}
+		}
+		head = lines[:insertAt]
+		tail = lines[insertAt:]
+		if insertAt == len(lines) {
+			head, tail = splitChangelogLinks(lines)
+		}
+	}
+
+	var out []string
+	out = append(out, trimBlankLines(head)...)
+	out = append(out, "")
+	out = append(out, "## ["+rel.Version+"] - "+rel.Date, "")
+	out = append(out, changelogSections(rel.Sections, pending)...)
+	if tail = trimBlankLines(tail); len(tail) > 0 {
+		out = append(out, tail...)
+	}
+	out = updateChangelogLinks(trimBlankLines(out), rel, unreleased >= 0)
+	return strings.Join(out, "\n") + "\n"
+}
+
+// changelogSections merges the hand-written unreleased entries with the
+// generated sections. Unreleased entries come first under matching headings;
+// headings only found in the unreleased section follow the generated ones.
+func changelogSections(sections []Section, pending []string) []string {
+	var titles []string
+	grouped := make(map[string][]string)
+	add := func(title string, lines ...string) {
+		if _, ok := grouped[title]; !ok {
+			titles = append(titles, title)
+		}
+		grouped[title] = append(grouped[title], lines...)
+	}
+
+	var manualTitles []string
+	manual := make(map[string][]string)
+	title := ""
+	for _, line := range pending {
+		if strings.HasPrefix(line, "### ") {
+			title = strings.TrimSpace(strings.TrimPrefix(line, "### "))
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if _, ok := manual[title]; !ok {
+			manualTitles = append(manualTitles, title)
+		}
+		manual[title] = append(manual[title], line)
+	}
+
+	for _, section := range sections {
+		if lines, ok := manual[section.Title]; ok {
+			add(section.Title, lines...)
+			delete(manual, section.Title)
+		}
+		for _, entry := range section.Entries {
+			add(section.Title, "- "+entry)
+		}
+	}
+	for _, t := range manualTitles {
+		if lines, ok := manual[t]; ok {
+			add(t, lines...)
+		}
+	}
+
+	var out []string
+	for _, t := range titles {
+		if t != "" {
+			out = append(out, "### "+t, "")
+		}
+		out = append(out, grouped[t]...)
+		out = append(out, "")
+	}
+	return out
+}
+
+// updateChangelogLinks points the Unreleased link at the new tag and adds
+// the link of the new version above the previous ones.
+func updateChangelogLinks(lines []string, rel changelogRelease, hasUnreleased bool) []string {
+	versionLink := "[" + rel.Version + "]: " + rel.VersionLink
+	unreleasedLink := "[Unreleased]: " + rel.UnreleasedLink
+
+	first := -1
+	for i, line := range lines {
+		match := changelogLinkPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if strings.EqualFold(match[1], "Unreleased") {
+			lines[i] = unreleasedLink
+			return insertLine(lines, i+1, versionLink)
+		}
+		if first < 0 {
+			first = i
+		}
+	}
+	if first >= 0 {
+		if hasUnreleased {
+			return insertLine(lines, first, unreleasedLink, versionLink)
+		}
+		return insertLine(lines, first, versionLink)
+	}
+
+	lines = append(lines, "")
+	if hasUnreleased {
+		lines = append(lines, unreleasedLink)
+	}
+	return append(lines, versionLink)
+}
+
+// splitChangelogLinks separates the trailing link reference definitions from
+// the section body.
+func splitChangelogLinks(lines []string) ([]string, []string) {
+	start := len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line != "" && !changelogLinkPattern.MatchString(line) {
+			break
+		}
+		start = i
+	}
+	return lines[:start], lines[start:]
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func insertLine(lines []string, at int, inserted ...string) []string {
+	out := make([]string, 0, len(lines)+len(inserted))
+	out = append(out, lines[:at]...)
+	out = append(out, inserted...)
+	return append(out, lines[at:]...)
+}
@@ -0,0 +1,88 @@
+package release
+
+import "testing"
+
+var testChangelogRelease = changelogRelease{
+	Version: "1.2.0",
+	Date:    "2026-03-02",
+	Sections: []Section{
+		{Title: "Features", Entries: []string{"add CSV export by Linus"}},
+		{Title: "Fixes", Entries: []string{"**auth:** refresh expired tokens by Ada"}},
+	},
+	VersionLink:    "https://github.com/acme/app/compare/v1.1.0...v1.2.0",
+	UnreleasedLink: "https://github.com/acme/app/compare/v1.2.0...HEAD",
+}
+
+func TestInsertChangelogRelease_MovesUnreleasedEntries(t *testing.T) {
+	content := "# Changelog\n\n" +
+		"## [Unreleased]\n\n" +
+		"### Fixes\n\n" +
+		"- Handle empty exports\n\n" +
+		"### Security\n\n" +
+		"- Escape report titles\n\n" +
+		"## [1.1.0] - 2026-02-01\n\n" +
+		"### Features\n\n" +
+		"- Add PDF export\n\n" +
+		"[Unreleased]: https://github.com/acme/app/compare/v1.1.0...HEAD\n" +
+		"[1.1.0]: https://github.com/acme/app/compare/v1.0.0...v1.1.0\n"
+
+	expected := "# Changelog\n\n" +
+		"## [Unreleased]\n\n" +
+		"## [1.2.0] - 2026-03-02\n\n" +
+		"### Features\n\n" +
+		"- add CSV export by Linus\n\n" +
+		"### Fixes\n\n" +
+		"- Handle empty exports\n" +
+		"- **auth:** refresh expired tokens by Ada\n\n" +
+		"### Security\n\n" +
+		"- Escape report titles\n\n" +
+		"## [1.1.0] - 2026-02-01\n\n" +
+		"### Features\n\n" +
+		"- Add PDF export\n\n" +
+		"[Unreleased]: https://github.com/acme/app/compare/v1.2.0...HEAD\n" +
+		"[1.2.0]: https://github.com/acme/app/compare/v1.1.0...v1.2.0\n" +
+		"[1.1.0]: https://github.com/acme/app/compare/v1.0.0...v1.1.0\n"
+
+	if got := insertChangelogRelease(content, testChangelogRelease); got != expected {
+		t.Fatalf("unexpected changelog:\n%s", got)
+	}
+}
+
+func TestInsertChangelogRelease_WithoutUnreleasedSection(t *testing.T) {
+	content := "# Changelog\n\n" +
+		"## [1.1.0] - 2026-02-01\n\n" +
+		"- Add PDF export\n"
+
+	expected := "# Changelog\n\n" +
+		"## [1.2.0] - 2026-03-02\n\n" +
+		"### Features\n\n" +
+		"- add CSV export by Linus\n\n" +
+		"### Fixes\n\n" +
+		"- **auth:** refresh expired tokens by Ada\n\n" +
+		"## [1.1.0] - 2026-02-01\n\n" +
+		"- Add PDF export\n\n" +
+		"[1.2.0]: https://github.com/acme/app/compare/v1.1.0...v1.2.0\n"
+
+	if got := insertChangelogRelease(content, testChangelogRelease); got != expected {
+		t.Fatalf("unexpected changelog:\n%s", got)
+	}
+}
+
+func TestInsertChangelogRelease_NewFile(t *testing.T) {
+	got := insertChangelogRelease(changelogHeader, changelogRelease{
+		Version:        "1.0.1",
+		Date:           "2026-03-02",
+		Sections:       []Section{{Entries: []string{"**Fix typo** by Ada"}}},
+		VersionLink:    "https://github.com/acme/app/compare/v1.0.0...v1.0.1",
+		UnreleasedLink: "https://github.com/acme/app/compare/v1.0.1...HEAD",
+	})
+
+	expected := changelogHeader + "\n" +
+		"## [1.0.1] - 2026-03-02\n\n" +
+		"- **Fix typo** by Ada\n\n" +
+		"[Unreleased]: https://github.com/acme/app/compare/v1.0.1...HEAD\n" +
+		"[1.0.1]: https://github.com/acme/app/compare/v1.0.0...v1.0.1\n"
+	if got != expected {
+		t.Fatalf("unexpected changelog:\n%s", got)
+	}
+}
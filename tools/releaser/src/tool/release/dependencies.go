@@ -0,0 +1,41 @@
+package release
+
+import (
+	"fmt"
+
+	"releaser/tool/composer"
+	"releaser/tool/gitops"
+	"releaser/tool/output"
+	"releaser/tool/shared"
+)
+
+// dependencyUpdates lists the composer.lock changes between the previous tag
+// and HEAD. It returns nil when the lock file is missing or unchanged.
+func dependencyUpdates(cfg *shared.Config) []composer.Upgrade {
+	lockFile := cfg.RepoPath("composer.lock")
+	oldSrc, oldOK, err := gitops.FileAtRef(cfg.BaseDir, cfg.OldTag, lockFile)
+	if err != nil || !oldOK {
+		return nil
+	}
+	newSrc, newOK, err := gitops.FileAtRef(cfg.BaseDir, "HEAD", lockFile)
+	if err != nil || !newOK {
+		return nil
+	}
+
+	oldLock, err := composer.ParseLock([]byte(oldSrc))
+	if err != nil {
+		output.Warn("Failed to parse " + lockFile + " at " + cfg.OldTag + ": " + err.Error())
+		return nil
+	}
+	newLock, err := composer.ParseLock([]byte(newSrc))
+	if err != nil {
+		output.Warn("Failed to parse " + lockFile + " at HEAD: " + err.Error())
+		return nil
+	}
+
+	upgrades := composer.Upgrades(oldLock, newLock)
+	if len(upgrades) > 0 {
+		output.Verbose(fmt.Sprintf("Release notes include %d package update(s) from %s", len(upgrades), lockFile))
+	}
+	return upgrades
+}
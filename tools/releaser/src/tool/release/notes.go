@@ -0,0 +1,67 @@
+package release
+
+import (
+	"releaser/tool/conventional"
+	"releaser/tool/gitops"
+)
+
+// conventionalNotes groups the commits under Breaking Changes, Features,
+// Fixes and the other Conventional Commits headings. It reports false when
+// no commit follows the convention, in which case the flat list is used.
+// types are the configured extra commit types.
+func conventionalNotes(commits []gitops.Commit, types []string) ([]Section, bool) {
+	sections := make(map[string][]string)
+	found := false
+	for _, commit := range commits {
+		c, ok := conventional.Parse(commit.Subject, commit.Body, types...)
+		if !ok {
+			sections[conventional.OtherTitle] = append(sections[conventional.OtherTitle], commitLine("**"+commit.Subject+"**", commit.Author))
+			continue
+		}
+		found = true
+
+		text := c.Subject
+		if c.Scope != "" {
+			text = "**" + c.Scope + ":** " + text
+		}
+		line := commitLine(text, commit.Author)
+		if c.BreakingNote != "" {
+			line += "\n  - " + c.BreakingNote
+		}
+		title := c.SectionTitle()
+		sections[title] = append(sections[title], line)
+	}
+	if !found {
+		return nil, false
+	}
+
+	titles := []string{conventional.BreakingTitle}
+	for _, section := range conventional.Sections {
+		titles = append(titles, section.Title)
+	}
+	titles = append(titles, conventional.OtherTitle)
+
+	return orderedSections(titles, sections), true
+}
+
+// orderedSections lists the non-empty groups in the given title order.
+func orderedSections(titles []string, grouped map[string][]string) []Section {
+	var sections []Section
+	for _, title := range titles {
+		if len(grouped[title]) > 0 {
+			sections = append(sections, Section{Title: title, Entries: grouped[title]})
+		}
+	}
+	return sections
+}
+
+// commitLine renders a release notes entry crediting the commit author.
+func commitLine(text, author string) string {
+	if author == "dependabot[bot]" {
+		author = "dependabot"
+	}
+	if author == "" {
+		return text
+	}
+	return text + " by " + author
+}
@@ -0,0 +1,37 @@
+package release
+
+import (
+	"reflect"
+	"testing"
+
+	"releaser/tool/gitops"
+)
+
+func TestConventionalNotes_GroupsBySection(t *testing.T) {
+	commits := []gitops.Commit{
+		{Subject: "fix(auth): refresh expired tokens", Author: "Ada"},
+		{Subject: "feat: add CSV export", Author: "Linus"},
+		{Subject: "feat(api)!: remove v1 routes", Body: "BREAKING CHANGE: clients must use /v2", Author: "Grace"},
+		{Subject: "Bump guzzle to 7.9", Author: "dependabot[bot]"},
+	}
+
+	notes, grouped := conventionalNotes(commits, nil)
+	if !grouped {
+		t.Fatalf("expected grouped notes")
+	}
+	expected := []Section{
+		{Title: "Breaking Changes", Entries: []string{"**api:** remove v1 routes by Grace\n  - clients must use /v2"}},
+		{Title: "Features", Entries: []string{"add CSV export by Linus"}},
+		{Title: "Fixes", Entries: []string{"**auth:** refresh expired tokens by Ada"}},
+		{Title: "Other Changes", Entries: []string{"**Bump guzzle to 7.9** by dependabot"}},
+	}
+	if !reflect.DeepEqual(notes, expected) {
+		t.Fatalf("unexpected notes: %+v", notes)
+	}
+}
+
+func TestConventionalNotes_FallsBackWithoutConventionalCommits(t *testing.T) {
+	if _, grouped := conventionalNotes([]gitops.Commit{{Subject: "Update README"}, {Subject: "WIP: notes export"}}, nil); grouped {
+		t.Fatalf("expected flat notes when no commit follows the convention")
+	}
+}
@@ -0,0 +1,232 @@
+package release
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"releaser/tool/conventional"
+	"releaser/tool/githubapi"
+	"releaser/tool/gitops"
+	"releaser/tool/output"
+	"releaser/tool/shared"
+)
+
+// defaultNoteSections mirror the labels GitHub creates for new repositories.
+var defaultNoteSections = []shared.NotesSection{
+	{Title: conventional.BreakingTitle, Labels: []string{"breaking", "breaking-change"}},
+	{Title: "Features", Labels: []string{"feature", "enhancement"}},
+	{Title: "Fixes", Labels: []string{"bug", "fix"}},
+	{Title: "Dependencies", Labels: []string{"dependencies"}},
+	{Title: "Documentation", Labels: []string{"documentation"}},
+}
+
+var defaultExcludeLabels = []string{"skip-changelog"}
+
+// maxPullLookups caps the commits/{sha}/pulls requests of one release;
+// larger ranges use the commit-based notes.
+const maxPullLookups = 250
+
+// maxContributorLookups caps the search requests behind New Contributors,
+// keeping them under the search rate limit of 30 requests a minute.
+const maxContributorLookups = 20
+
+// pullRequestSource looks up pull request data; tests replace the GitHub
+// implementation with canned responses.
+type pullRequestSource struct {
+	pullsForCommit      func(sha string) ([]githubapi.PullRequest, error)
+	isFirstContribution func(login, before string) (bool, error)
+	// mergedCommits lists the commits a merge commit brought in, which
+	// belong to the pull request found for the merge.
+	mergedCommits func(sha string) ([]string, error)
+}
+
+func githubPullRequests(cfg *shared.Config) pullRequestSource {
+	return pullRequestSource{
+		pullsForCommit: func(sha string) ([]githubapi.PullRequest, error) {
+			return githubapi.PullsForCommit(cfg, sha)
+		},
+		isFirstContribution: func(login, before string) (bool, error) {
+			return githubapi.IsFirstContribution(cfg, login, before)
+		},
+		mergedCommits: func(sha string) ([]string, error) {
+			return gitops.MergedCommits(cfg.BaseDir, sha)
+		},
+	}
+}
+
+// pullRequestNotes lists the merged pull requests of the commit range grouped
+// by label, plus commits pushed without a pull request under Other Changes.
+// It also returns the New Contributors list. The boolean is false when no
+// pull request was found or GitHub could not be queried, in which case the
+// commit-based notes are used.
+func pullRequestNotes(commits []gitops.Commit, src pullRequestSource, settings shared.NotesSettings) ([]Section, []string, bool) {
+	seen := make(map[int]bool)
+	covered := make(map[string]bool)
+	lookups := 0
+	var pulls []githubapi.PullRequest
+	var direct []gitops.Commit
+	for _, commit := range commits {
+		if covered[commit.Hash] {
+			continue
+		}
+		if lookups == maxPullLookups {
+			output.Warn("More than " + strconv.Itoa(maxPullLookups) + " commits to look up pull requests for; using commit-based notes")
+			return nil, nil, false
+		}
+		lookups++
+		prs, err := src.pullsForCommit(commit.Hash)
+		if errors.Is(err, githubapi.ErrRateLimited) {
+			output.Warn("GitHub API rate limit reached while looking up pull requests; using commit-based notes")
+			return nil, nil, false
+		}
+		if err != nil {
+			output.Warn("Failed to look up pull requests for " + commit.Hash + ": " + err.Error())
+			return nil, nil, false
+		}
+		if len(prs) == 0 {
+			direct = append(direct, commit)
+			continue
+		}
+		for _, pr := range prs {
+			if !seen[pr.Number] {
+				seen[pr.Number] = true
+				pulls = append(pulls, pr)
+			}
+		}
+		if src.mergedCommits == nil {
+			continue
+		}
+		merged, err := src.mergedCommits(commit.Hash)
+		if err != nil {
+			output.Verbose("Failed to list the commits merged by " + commit.ShortHash() + ": " + err.Error())
+		}
+		for _, hash := range merged {
+			covered[hash] = true
+		}
+	}
+	if len(pulls) == 0 {
+		return nil, nil, false
+	}
+	output.Verbose("Pull requests found for release notes: " + strconv.Itoa(len(pulls)))
+
+	sections := settings.Sections
+	if len(sections) == 0 {
+		sections = defaultNoteSections
+	}
+	exclude := settings.ExcludeLabels
+	if len(exclude) == 0 {
+		exclude = defaultExcludeLabels
+	}
+
+	grouped := make(map[string][]string)
+	for _, pr := range pulls {
+		if hasAnyLabel(pr.Labels, exclude) {
+			output.VeryVerbose("Excluding #" + strconv.Itoa(pr.Number) + " from release notes")
+			continue
+		}
+		title := pullSectionTitle(pr, sections)
+		grouped[title] = append(grouped[title], pullLine(pr))
+	}
+	for _, commit := range direct {
+		grouped[conventional.OtherTitle] = append(grouped[conventional.OtherTitle], commitLine("**"+commit.Subject+"**", commit.Author))
+	}
+
+	return orderedSections(noteSectionOrder(sections), grouped), newContributors(pulls, src), true
+}
+
+// pullSectionTitle picks the first configured section sharing a label with
+// the pull request, then falls back to the Conventional Commits type of its
+// title.
+func pullSectionTitle(pr githubapi.PullRequest, sections []shared.NotesSection) string {
+	for _, section := range sections {
+		if hasAnyLabel(pr.Labels, section.Labels) {
+			return section.Title
+		}
+	}
+	if c, ok := conventional.Parse(pr.Title, ""); ok {
+		return c.SectionTitle()
+	}
+	return conventional.OtherTitle
+}
+
+// noteSectionOrder lists the configured titles followed by the Conventional
+// Commits headings not already configured, ending with Other Changes.
+func noteSectionOrder(sections []shared.NotesSection) []string {
+	seen := make(map[string]bool)
+	var titles []string
+	add := func(title string) {
+		if !seen[title] {
+			seen[title] = true
+			titles = append(titles, title)
+		}
+	}
+	for _, section := range sections {
+		add(section.Title)
+	}
+	add(conventional.BreakingTitle)
+	for _, section := range conventional.Sections {
+		add(section.Title)
+	}
+	add(conventional.OtherTitle)
+	return titles
+}
+
+// newContributors lists authors whose first merged pull request is part of
+// this release. Bots are skipped. The list is left out when it would take
+// more than maxContributorLookups searches or the search is rate limited.
+func newContributors(pulls []githubapi.PullRequest, src pullRequestSource) []string {
+	first := make(map[string]githubapi.PullRequest)
+	var logins []string
+	for _, pr := range pulls {
+		if pr.Login == "" || strings.HasSuffix(pr.Login, "[bot]") {
+			continue
+		}
+		existing, ok := first[pr.Login]
+		if !ok {
+			logins = append(logins, pr.Login)
+		}
+		if !ok || pr.MergedAt < existing.MergedAt {
+			first[pr.Login] = pr
+		}
+	}
+
+	if len(logins) > maxContributorLookups {
+		output.Warn("More than " + strconv.Itoa(maxContributorLookups) + " contributors in this release; skipping New Contributors")
+		return nil
+	}
+
+	var out []string
+	for _, login := range logins {
+		pr := first[login]
+		isNew, err := src.isFirstContribution(login, pr.MergedAt)
+		if errors.Is(err, githubapi.ErrRateLimited) {
+			output.Warn("GitHub search rate limit reached; skipping New Contributors")
+			return nil
+		}
+		if err != nil {
+			output.Warn("Failed to look up previous contributions: " + err.Error())
+			return nil
+		}
+		if isNew {
+			out = append(out, "@"+login+" made their first contribution in #"+strconv.Itoa(pr.Number))
+		}
+	}
+	return out
+}
+
+func pullLine(pr githubapi.PullRequest) string {
+	login := strings.TrimSuffix(pr.Login, "[bot]")
+	return pr.Title + " by @" + login + " in #" + strconv.Itoa(pr.Number)
+}
+
+func hasAnyLabel(labels, wanted []string) bool {
+	for _, label := range labels {
+		for _, w := range wanted {
+			if strings.EqualFold(label, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"releaser/tool/githubapi"
+	"releaser/tool/gitops"
+	"releaser/tool/shared"
+)
+
+func TestPullRequestNotes_GroupsByLabel(t *testing.T) {
+	pulls := map[string][]githubapi.PullRequest{
+		"c1": {{Number: 12, Title: "Add CSV export", Login: "ada", Labels: []string{"enhancement"}, MergedAt: "2026-03-02T10:00:00Z"}},
+		"c2": {{Number: 12, Title: "Add CSV export", Login: "ada", Labels: []string{"enhancement"}, MergedAt: "2026-03-02T10:00:00Z"}},
+		"c3": {{Number: 14, Title: "fix(auth): refresh tokens", Login: "grace", MergedAt: "2026-03-03T10:00:00Z"}},
+		"c4": {{Number: 15, Title: "Bump guzzle", Login: "dependabot[bot]", Labels: []string{"dependencies"}, MergedAt: "2026-03-04T10:00:00Z"}},
+		"c5": {{Number: 16, Title: "Tweak CI cache", Login: "linus", Labels: []string{"skip-changelog"}, MergedAt: "2026-03-05T10:00:00Z"}},
+	}
+	src := pullRequestSource{
+		pullsForCommit: func(sha string) ([]githubapi.PullRequest, error) {
+			return pulls[sha], nil
+		},
+		isFirstContribution: func(login, before string) (bool, error) {
+			return login == "grace", nil
+		},
+	}
+	commits := []gitops.Commit{
+		{Hash: "c1"}, {Hash: "c2"}, {Hash: "c3"}, {Hash: "c4"}, {Hash: "c5"},
+		{Hash: "c6", Subject: "Hotfix typo", Author: "Ada Lovelace"},
+	}
+
+	body, contributors, ok := pullRequestNotes(commits, src, shared.NotesSettings{})
+	if !ok {
+		t.Fatalf("expected pull request notes")
+	}
+	expectedBody := []Section{
+		{Title: "Features", Entries: []string{"Add CSV export by @ada in #12"}},
+		{Title: "Fixes", Entries: []string{"fix(auth): refresh tokens by @grace in #14"}},
+		{Title: "Dependencies", Entries: []string{"Bump guzzle by @dependabot in #15"}},
+		{Title: "Other Changes", Entries: []string{"**Hotfix typo** by Ada Lovelace"}},
+	}
+	if !reflect.DeepEqual(body, expectedBody) {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+	if !reflect.DeepEqual(contributors, []string{"@grace made their first contribution in #14"}) {
+		t.Fatalf("unexpected contributors: %+v", contributors)
+	}
+}
+
+func TestPullRequestNotes_FallsBackOnAPIError(t *testing.T) {
+	src := pullRequestSource{
+		pullsForCommit: func(sha string) ([]githubapi.PullRequest, error) {
+			return nil, errors.New("rate limited")
+		},
+	}
+	if _, _, ok := pullRequestNotes([]gitops.Commit{{Hash: "c1"}}, src, shared.NotesSettings{}); ok {
+		t.Fatalf("expected fallback when GitHub cannot be queried")
+	}
+}
+
+func TestPullRequestNotes_SkipsCommitsOfFoundMerge(t *testing.T) {
+	var looked []string
+	src := pullRequestSource{
+		pullsForCommit: func(sha string) ([]githubapi.PullRequest, error) {
+			looked = append(looked, sha)
+			if sha == "m1" {
+				return []githubapi.PullRequest{{Number: 12, Title: "Add CSV export", Login: "ada"}}, nil
+			}
+			return nil, nil
+		},
+		isFirstContribution: func(login, before string) (bool, error) {
+			return false, nil
+		},
+		mergedCommits: func(sha string) ([]string, error) {
+			if sha == "m1" {
+				return []string{"c1", "c2"}, nil
+			}
+			return nil, nil
+		},
+	}
+	commits := []gitops.Commit{{Hash: "m1"}, {Hash: "c2"}, {Hash: "c1"}, {Hash: "c0", Subject: "Hotfix typo"}}
+
+	if _, _, ok := pullRequestNotes(commits, src, shared.NotesSettings{}); !ok {
+		t.Fatalf("expected pull request notes")
+	}
+	if want := []string{"m1", "c0"}; !reflect.DeepEqual(looked, want) {
+		t.Fatalf("expected lookups %v, got %v", want, looked)
+	}
+}
+
+func TestPullRequestNotes_FallsBackWhenRateLimited(t *testing.T) {
+	calls := 0
+	src := pullRequestSource{
+		pullsForCommit: func(sha string) ([]githubapi.PullRequest, error) {
+			calls++
+			return nil, fmt.Errorf("lookup %s: %w", sha, githubapi.ErrRateLimited)
+		},
+	}
+	if _, _, ok := pullRequestNotes([]gitops.Commit{{Hash: "c1"}, {Hash: "c2"}}, src, shared.NotesSettings{}); ok {
+		t.Fatalf("expected fallback when rate limited")
+	}
+	if calls != 1 {
+		t.Fatalf("expected to stop after the first rate limited request, got %d requests", calls)
+	}
+}
+
+func TestNewContributors_CapsSearches(t *testing.T) {
+	searches := 0
+	src := pullRequestSource{
+		isFirstContribution: func(login, before string) (bool, error) {
+			searches++
+			return true, nil
+		},
+	}
+	var pulls []githubapi.PullRequest
+	for i := 0; i <= maxContributorLookups; i++ {
+		pulls = append(pulls, githubapi.PullRequest{Number: i, Login: fmt.Sprintf("user%d", i)})
+	}
+	if got := newContributors(pulls, src); got != nil || searches != 0 {
+		t.Fatalf("expected no searches beyond the cap, got %d searches and %v", searches, got)
+	}
+	if got := newContributors(pulls[:2], src); len(got) != 2 {
+		t.Fatalf("expected both contributors under the cap, got %v", got)
+	}
+}
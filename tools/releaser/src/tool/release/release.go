@@ -4,26 +4,61 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"text/template"
 
 	"releaser/tool/gitops"
 	"releaser/tool/output"
+	"releaser/tool/plan"
 	"releaser/tool/shared"
-	"releaser/tool/version"
 )
 
-func CreateTag(cfg *shared.Config) error {
-	output.Verbose("CreateTag start: type=" + cfg.Type + " tag=" + cfg.NewTag)
-	if !cfg.Force {
-		ans := output.Ask(fmt.Sprintf("Are you sure you want to create a new %s %s? [Y/n] ", cfg.Type, cfg.NewTag))
-		switch strings.ToLower(version.DefaultYes(ans)) {
-		case "y", "yes":
-			// continue
-		default:
-			output.Info("Aborted.")
-			return errors.New("aborted")
-		}
+// ErrAborted is returned when the release is declined at the confirmation
+// prompt.
+var ErrAborted = errors.New("aborted")
+
+// Confirm asks before anything is written, unless --force or --yes is set.
+func Confirm(cfg *shared.Config) error {
+	if cfg.Force || cfg.Yes || cfg.DryRun {
+		return nil
+	}
+	ans, err := output.Prompt(output.Decision{
+		Name:     shared.PromptConfirmRelease,
+		Question: fmt.Sprintf("Are you sure you want to create a new %s %s? [Y/n] ", cfg.Type, cfg.NewTag),
+		Default:  "y",
+	})
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(ans) {
+	case "y", "yes":
+		return nil
+	default:
+		output.Info("Aborted.")
+		return ErrAborted
+	}
+}
+
+// CommitRelease commits the files updated for the release, such as the
+// changelog, so the tag points at them.
+func CommitRelease(cfg *shared.Config) error {
+	if len(cfg.ReleaseFiles) == 0 {
+		output.Verbose("No release files changed; skipping release commit")
+		return nil
+	}
+	output.Info("Committing release " + cfg.NewTag + "...")
+	if err := gitops.Mutate(cfg, append([]string{"add", "--"}, cfg.ReleaseFiles...)...); err != nil {
+		output.Warn("Failed to stage release files")
+		return err
 	}
+	if err := gitops.Mutate(cfg, "commit", "-m", "Release "+cfg.NewTag); err != nil {
+		output.Warn("Failed to create release commit")
+		return err
+	}
+	return nil
+}
 
+func CreateTag(cfg *shared.Config) error {
+	output.Verbose("CreateTag start: type=" + cfg.Type + " tag=" + cfg.NewTag)
 	localTagExists, err := gitops.TagExists(cfg.BaseDir, cfg.NewTag)
 	if err != nil {
 		output.Warn("Failed to check if tag already exists locally")
@@ -33,7 +68,7 @@ func CreateTag(cfg *shared.Config) error {
 		output.Info("Tag " + cfg.NewTag + " already exists locally; skipping tag creation.")
 	} else {
 		output.Info("Creating new tag " + cfg.NewTag + "...")
-		if _, err := gitops.Run(cfg.BaseDir, "tag", cfg.NewTag); err != nil {
+		if err := gitops.Mutate(cfg, "tag", cfg.NewTag); err != nil {
 			output.Warn("Failed to create tag " + cfg.NewTag)
 			return err
 		}
@@ -44,6 +79,7 @@ func CreateTag(cfg *shared.Config) error {
 		output.Warn("Failed to determine branch sync state")
 		return err
 	}
+	ahead += len(cfg.Plan.Commits())
 
 	shouldPushCommits := true
 	if hasUpstream {
@@ -65,7 +101,9 @@ func CreateTag(cfg *shared.Config) error {
 
 	if shouldPushCommits {
 		output.Info("Pushing commits...")
-		if _, err := gitops.Run(cfg.BaseDir, "push"); err != nil {
+		if cfg.Plan != nil {
+			cfg.Plan.Git([]string{"push"}, unpushedCommits(cfg, hasUpstream)...)
+		} else if _, err := gitops.Run(cfg.BaseDir, "push"); err != nil {
 			output.Warn("Failed to push commits")
 			return err
 		}
@@ -82,7 +120,7 @@ func CreateTag(cfg *shared.Config) error {
 	}
 
 	output.Info("Pushing tag " + cfg.NewTag + "...")
-	if _, err := gitops.Run(cfg.BaseDir, "push", "origin", cfg.NewTag); err != nil {
+	if err := gitops.Mutate(cfg, "push", "origin", cfg.NewTag); err != nil {
 		output.Warn("Failed to push tag " + cfg.NewTag)
 		return err
 	}
@@ -90,46 +128,96 @@ func CreateTag(cfg *shared.Config) error {
 	return nil
 }
 
+// unpushedCommits lists the commits a dry run would push, including the
+// commits it only recorded.
+func unpushedCommits(cfg *shared.Config, hasUpstream bool) []string {
+	var lines []string
+	if hasUpstream {
+		commits, err := gitops.Commits(cfg.BaseDir, "@{upstream}..HEAD")
+		if err != nil {
+			output.Warn("Failed to list unpushed commits: " + err.Error())
+		}
+		for _, commit := range commits {
+			lines = append(lines, commit.ShortHash()+" "+commit.Subject)
+		}
+	} else {
+		lines = append(lines, "(no upstream branch; pushing the current branch)")
+	}
+	for _, message := range cfg.Plan.Commits() {
+		lines = append(lines, "(new) "+message)
+	}
+	return lines
+}
+
+// Describe completes the plan recorded during a dry run with the versions,
+// notes and rules of the release.
+func Describe(cfg *shared.Config) *plan.Plan {
+	p := cfg.Plan
+	if cfg.Component != nil {
+		p.Component = cfg.Component.Name
+	}
+	p.Type = cfg.Type
+	p.OldTag = cfg.OldTag
+	p.OldVersion = cfg.OldVer
+	p.NewTag = cfg.NewTag
+	p.NewVersion = cfg.NewVer
+	p.Prerelease = cfg.Prerelease
+	p.Notes = cfg.Changes
+	p.Rules = nil
+	for _, rule := range cfg.Rules {
+		p.Rules = append(p.Rules, plan.Rule{File: rule.File, Severity: rule.Severity, Reason: rule.Reason})
+	}
+	return p
+}
+
 func BuildChanges(cfg *shared.Config) error {
 	output.Info("Detecting changes for release notes...")
-	log, err := gitops.Run(cfg.BaseDir, "log", cfg.OldTag+"..HEAD", "--pretty=format:%s####%an")
-	if err != nil || strings.TrimSpace(log) == "" {
-		cfg.Changes = "## What's Changed\n\n"
+	tmpl, err := notesTemplate(cfg)
+	if err != nil {
+		return err
+	}
+	notes := newNotes(cfg)
+
+	commits, err := gitops.Commits(cfg.BaseDir, cfg.OldTag+"..HEAD", cfg.Pathspec()...)
+	if err != nil || len(commits) == 0 {
 		if err != nil {
-			cfg.Changes += "No commits found since " + cfg.OldTag + "\n\n"
+			notes.Notice = "No commits found since " + cfg.OldTag
 		} else {
-			cfg.Changes += "No commits found\n\n"
+			notes.Notice = "No commits found"
 		}
-		cfg.Changes += fmt.Sprintf("**Full Changelog**: https://github.com/%s/compare/%s...%s", cfg.Repo, cfg.OldTag, cfg.NewTag)
 		output.Verbose("Release notes generated with empty commit range fallback")
-		return nil
+		return renderChanges(cfg, tmpl, notes)
 	}
+	notes.Commits = commits
 
-	var body strings.Builder
-	for _, line := range strings.Split(strings.TrimSpace(log), "\n") {
-		if line == "" {
-			continue
-		}
-		parts := strings.SplitN(line, "####", 2)
-		msg := parts[0]
-		author := ""
-		if len(parts) == 2 {
-			author = parts[1]
-		}
-		if author == "dependabot[bot]" {
-			author = "dependabot"
-		}
-		body.WriteString("- **")
-		body.WriteString(msg)
-		body.WriteString("**")
-		if author != "" {
-			body.WriteString(" by ")
-			body.WriteString(author)
+	source := "merged pull requests"
+	grouped := false
+	if cfg.Token != "" {
+		notes.Sections, notes.NewContributors, grouped = pullRequestNotes(commits, githubPullRequests(cfg), cfg.Project.Notes)
+	}
+	if !grouped {
+		notes.Sections, grouped = conventionalNotes(commits, cfg.Project.Commits.Types)
+		source = "Conventional Commits"
+	}
+	if !grouped {
+		var entries []string
+		for _, commit := range commits {
+			entries = append(entries, commitLine("**"+commit.Subject+"**", commit.Author))
 		}
-		body.WriteString("\n")
+		notes.Sections = []Section{{Entries: entries}}
+		source = "commit log"
 	}
+	notes.Dependencies = dependencyUpdates(cfg)
 
-	cfg.Changes = "## What's Changed\n\n" + body.String() + "\n**Full Changelog**: https://github.com/" + cfg.Repo + "/compare/" + cfg.OldTag + "..." + cfg.NewTag
-	output.Verbose("Release notes generated from commit log")
-	return nil
+	output.Verbose("Release notes generated from " + source)
+	return renderChanges(cfg, tmpl, notes)
+}
+
+func renderChanges(cfg *shared.Config, tmpl *template.Template, notes Notes) error {
+	changes, err := renderNotes(tmpl, notes)
+	if err != nil {
+		return err
+	}
+	cfg.Changes = changes
+	return updateChangelog(cfg, notes)
 }
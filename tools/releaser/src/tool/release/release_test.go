@@ -0,0 +1,45 @@
+package release
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"releaser/tool/output"
+	"releaser/tool/output/outputtest"
+	"releaser/tool/shared"
+)
+
+func TestConfirm(t *testing.T) {
+	cases := []struct {
+		answer string
+		want   error
+	}{
+		{answer: "default", want: nil},
+		{answer: "yes", want: nil},
+		{answer: "n", want: ErrAborted},
+	}
+	for _, c := range cases {
+		script := &outputtest.Script{Answers: map[string]string{shared.PromptConfirmRelease: c.answer}}
+		outputtest.Use(t, script)
+		err := Confirm(&shared.Config{Type: "minor", NewTag: "v1.2.0"})
+		if !errors.Is(err, c.want) {
+			t.Errorf("answer %q: expected %v, got %v", c.answer, c.want, err)
+		}
+		if !reflect.DeepEqual(script.Asked, []string{shared.PromptConfirmRelease}) {
+			t.Errorf("answer %q: unexpected prompts %v", c.answer, script.Asked)
+		}
+	}
+}
+
+func TestConfirm_NonInteractiveWithoutAnswer(t *testing.T) {
+	outputtest.Use(t, output.NonInteractive{})
+
+	var missing *output.MissingDecisionError
+	if err := Confirm(&shared.Config{Type: "minor", NewTag: "v1.2.0"}); !errors.As(err, &missing) || missing.Decision != shared.PromptConfirmRelease {
+		t.Fatalf("expected a MissingDecisionError for %s, got %v", shared.PromptConfirmRelease, err)
+	}
+	if err := Confirm(&shared.Config{Force: true}); err != nil {
+		t.Fatalf("expected --force to skip the prompt, got %v", err)
+	}
+}
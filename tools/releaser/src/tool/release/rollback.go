@@ -0,0 +1,133 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+
+	"releaser/tool/githubapi"
+	"releaser/tool/gitops"
+	"releaser/tool/journal"
+	"releaser/tool/output"
+	"releaser/tool/shared"
+)
+
+// Rollback undoes the release of tag: it deletes the GitHub release and the
+// remote and local tags and, with revert, reverts the release commit. Only
+// the latest release, cfg.OldTag, can be rolled back without --force. The
+// rollback is confirmed first unless --yes is set. The returned record lists
+// what was removed, including when a later step failed.
+func Rollback(cfg *shared.Config, tag string, revert bool) (journal.Rollback, error) {
+	record := journal.Rollback{Tag: tag}
+	if cfg.Component != nil {
+		record.Component = cfg.Component.Name
+	}
+	if tag != cfg.OldTag && !cfg.Force {
+		return record, fmt.Errorf("%s is not the latest release (%s); use --force to roll it back anyway", tag, cfg.OldTag)
+	}
+
+	var releaseCommit *gitops.Commit
+	if revert {
+		commits, err := gitops.Commits(cfg.BaseDir, tag+"^!")
+		if err != nil {
+			return record, err
+		}
+		if len(commits) == 1 && commits[0].Subject == "Release "+tag {
+			releaseCommit = &commits[0]
+		} else {
+			output.Warn("Tag " + tag + " does not point at a release commit; nothing to revert")
+		}
+	}
+	if releaseCommit != nil {
+		if err := checkRevertPush(cfg); err != nil {
+			return record, err
+		}
+	}
+
+	if !cfg.Yes && !cfg.DryRun {
+		ans, err := output.Prompt(output.Decision{
+			Name:     shared.PromptConfirmRollback,
+			Question: "Are you sure you want to roll back " + tag + "? This deletes its GitHub release and tags. [y/N] ",
+			Default:  "n",
+		})
+		if err != nil {
+			return record, err
+		}
+		if a := strings.ToLower(ans); a != "y" && a != "yes" {
+			output.Info("Aborted.")
+			return record, ErrAborted
+		}
+	}
+
+	output.Info("Deleting GitHub release " + tag + "...")
+	url, err := githubapi.DeleteRelease(cfg, tag)
+	if err != nil {
+		output.Warn("Failed to delete GitHub release " + tag)
+		return record, err
+	}
+	if url == "" {
+		output.Info("No GitHub release found for " + tag + "; skipping.")
+	}
+	record.Release = url
+
+	remoteTagExists, err := gitops.RemoteTagExists(cfg.BaseDir, tag)
+	if err != nil {
+		output.Warn("Failed to check if tag exists on origin")
+		return record, err
+	}
+	if remoteTagExists {
+		output.Info("Deleting tag " + tag + " from origin...")
+		if err := gitops.Mutate(cfg, "push", "origin", "--delete", "refs/tags/"+tag); err != nil {
+			output.Warn("Failed to delete remote tag " + tag)
+			return record, err
+		}
+		record.RemoteTag = true
+	}
+
+	if releaseCommit != nil {
+		output.Info("Reverting release commit " + releaseCommit.ShortHash() + "...")
+		if err := gitops.Mutate(cfg, "revert", "--no-edit", releaseCommit.Hash); err != nil {
+			output.Warn("Failed to revert release commit " + releaseCommit.ShortHash())
+			return record, err
+		}
+		if err := gitops.Mutate(cfg, "push"); err != nil {
+			output.Warn("Failed to push the revert of " + releaseCommit.ShortHash())
+			return record, err
+		}
+		record.Reverted = releaseCommit.Hash
+	}
+
+	localTagExists, err := gitops.TagExists(cfg.BaseDir, tag)
+	if err != nil {
+		output.Warn("Failed to check if tag exists locally")
+		return record, err
+	}
+	if localTagExists {
+		output.Info("Deleting local tag " + tag + "...")
+		if err := gitops.Mutate(cfg, "tag", "-d", tag); err != nil {
+			output.Warn("Failed to delete local tag " + tag)
+			return record, err
+		}
+		record.LocalTag = true
+	}
+	return record, nil
+}
+
+// checkRevertPush makes sure the revert of the release commit can be pushed
+// before anything is deleted, as CreateTag does before pushing the release:
+// a push failing after the release and tags are gone would leave the
+// rollback half done.
+func checkRevertPush(cfg *shared.Config) error {
+	ahead, behind, hasUpstream, err := gitops.AheadBehind(cfg.BaseDir)
+	if err != nil {
+		output.Warn("Failed to determine branch sync state")
+		return err
+	}
+	if !hasUpstream {
+		return fmt.Errorf("branch has no upstream to push the revert to; set one or roll back without --revert")
+	}
+	output.Verbose(fmt.Sprintf("Branch sync state: ahead=%d behind=%d", ahead, behind))
+	if ahead > 0 || behind > 0 {
+		return fmt.Errorf("branch is not in sync with upstream (ahead %d, behind %d); pull or push it before rolling back with --revert", ahead, behind)
+	}
+	return nil
+}
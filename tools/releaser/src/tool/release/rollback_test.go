@@ -0,0 +1,108 @@
+package release
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"releaser/tool/output"
+	"releaser/tool/output/outputtest"
+	"releaser/tool/shared"
+)
+
+func TestRollback_RefusesOlderTagWithoutForce(t *testing.T) {
+	script := &outputtest.Script{}
+	outputtest.Use(t, script)
+
+	_, err := Rollback(&shared.Config{OldTag: "v1.2.0"}, "v1.1.0", false)
+	if err == nil || !strings.Contains(err.Error(), "v1.1.0 is not the latest release (v1.2.0)") {
+		t.Fatalf("expected the older tag to be refused, got %v", err)
+	}
+	if len(script.Asked) != 0 {
+		t.Fatalf("expected no prompt before refusing, got %v", script.Asked)
+	}
+}
+
+func TestRollback_Confirmation(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  shared.Config
+		tag  string
+	}{
+		{name: "latest", cfg: shared.Config{OldTag: "v1.2.0"}, tag: "v1.2.0"},
+		{name: "older tag with --force", cfg: shared.Config{OldTag: "v1.2.0", Force: true}, tag: "v1.1.0"},
+	}
+	for _, c := range cases {
+		for _, answer := range []string{"default", "n", "no"} {
+			t.Run(c.name+"/"+answer, func(t *testing.T) {
+				script := &outputtest.Script{Answers: map[string]string{shared.PromptConfirmRollback: answer}}
+				outputtest.Use(t, script)
+
+				cfg := c.cfg
+				record, err := Rollback(&cfg, c.tag, false)
+				if !errors.Is(err, ErrAborted) {
+					t.Fatalf("expected the declined rollback to abort, got %v", err)
+				}
+				if !reflect.DeepEqual(script.Asked, []string{shared.PromptConfirmRollback}) {
+					t.Fatalf("expected the rollback to be confirmed, got %v", script.Asked)
+				}
+				if record.Release != "" || record.RemoteTag || record.LocalTag || record.Reverted != "" {
+					t.Fatalf("expected nothing to be removed, got %+v", record)
+				}
+			})
+		}
+	}
+}
+
+func TestRollback_NonInteractiveWithoutAnswer(t *testing.T) {
+	outputtest.Use(t, output.NonInteractive{})
+
+	_, err := Rollback(&shared.Config{OldTag: "v1.2.0", Force: true}, "v1.1.0", false)
+	var missing *output.MissingDecisionError
+	if !errors.As(err, &missing) || missing.Decision != shared.PromptConfirmRollback {
+		t.Fatalf("expected --force not to answer the confirmation, got %v", err)
+	}
+}
+
+func TestRollback_RevertRequiresSyncedBranch(t *testing.T) {
+	remote := t.TempDir()
+	dir := t.TempDir()
+	git := func(dir string, args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	git(remote, "init", "-q", "--bare")
+	git(dir, "init", "-q")
+	git(dir, "config", "user.email", "test@example.com")
+	git(dir, "config", "user.name", "test")
+	git(dir, "commit", "-q", "--allow-empty", "-m", "Release v1.2.0")
+	git(dir, "tag", "v1.2.0")
+
+	cases := []struct {
+		name  string
+		setup func()
+		want  string
+	}{
+		{name: "no upstream", setup: func() {}, want: "no upstream"},
+		{name: "ahead of upstream", setup: func() {
+			git(dir, "remote", "add", "origin", remote)
+			git(dir, "push", "-q", "-u", "origin", "HEAD")
+			git(dir, "commit", "-q", "--allow-empty", "-m", "unpushed")
+			git(dir, "tag", "-f", "v1.2.0", "HEAD^")
+		}, want: "ahead 1, behind 0"},
+	}
+	for _, c := range cases {
+		c.setup()
+		record, err := Rollback(&shared.Config{BaseDir: dir, OldTag: "v1.2.0", Yes: true}, "v1.2.0", true)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Fatalf("%s: expected the rollback to stop with %q, got %v", c.name, c.want, err)
+		}
+		if record.Release != "" || record.RemoteTag || record.LocalTag || record.Reverted != "" {
+			t.Fatalf("%s: expected nothing to be removed, got %+v", c.name, record)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package release
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"releaser/tool/composer"
+	"releaser/tool/gitops"
+	"releaser/tool/shared"
+)
+
+// Notes is the data release notes templates are rendered with.
+type Notes struct {
+	Repo       string
+	OldTag     string
+	NewTag     string
+	Type       string
+	CompareURL string
+	// Notice replaces the sections when the commit range could not be
+	// listed or is empty.
+	Notice          string
+	Commits         []gitops.Commit
+	Sections        []Section
+	NewContributors []string
+	Dependencies    []composer.Upgrade
+	Rules           []shared.Rule
+}
+
+// Section is a group of release notes entries. The flat commit list is a
+// single section without a title.
+type Section struct {
+	Title   string
+	Entries []string
+}
+
+const defaultNotesTemplate = `## What's Changed
+
+{{if .Notice}}{{.Notice}}
+
+{{end}}{{range .Sections}}{{if .Title}}### {{.Title}}
+
+{{end}}{{range .Entries}}- {{.}}
+{{end}}
+{{end}}{{if .NewContributors}}## New Contributors
+
+{{range .NewContributors}}- {{.}}
+{{end}}
+{{end}}{{if .Dependencies}}## Dependency Updates
+
+{{range .Dependencies}}- {{.}}
+{{end}}
+{{end}}**Full Changelog**: {{.CompareURL}}`
+
+var notesFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+func newNotes(cfg *shared.Config) Notes {
+	return Notes{
+		Repo:       cfg.Repo,
+		OldTag:     cfg.OldTag,
+		NewTag:     cfg.NewTag,
+		Type:       cfg.Type,
+		CompareURL: "https://github.com/" + cfg.Repo + "/compare/" + cfg.OldTag + "..." + cfg.NewTag,
+		Rules:      cfg.Rules,
+	}
+}
+
+// notesTemplate returns the repository-local template configured in
+// .releaser.json, or the built-in one.
+func notesTemplate(cfg *shared.Config) (*template.Template, error) {
+	path := cfg.Project.Notes.Template
+	if path == "" {
+		return parseNotesTemplate("default", defaultNotesTemplate)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cfg.BaseDir, path)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read release notes template: %w", err)
+	}
+	tmpl, err := parseNotesTemplate(filepath.Base(path), string(b))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse release notes template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+func parseNotesTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(notesFuncs).Parse(text)
+}
+
+func renderNotes(tmpl *template.Template, notes Notes) (string, error) {
+	var out strings.Builder
+	if err := tmpl.Execute(&out, notes); err != nil {
+		return "", fmt.Errorf("Failed to render release notes: %w", err)
+	}
+	return out.String(), nil
+}
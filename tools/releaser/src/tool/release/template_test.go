@@ -0,0 +1,93 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"releaser/tool/composer"
+	"releaser/tool/gitops"
+	"releaser/tool/shared"
+)
+
+func TestDefaultNotesTemplate_MatchesLayout(t *testing.T) {
+	tmpl, err := notesTemplate(&shared.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes := Notes{
+		CompareURL: "https://github.com/acme/app/compare/v1.0.0...v1.1.0",
+		Sections: []Section{
+			{Title: "Breaking Changes", Entries: []string{"**api:** remove v1 routes by Grace\n  - clients must use /v2"}},
+			{Title: "Fixes", Entries: []string{"refresh tokens by @ada in #14"}},
+		},
+		NewContributors: []string{"@ada made their first contribution in #14"},
+		Dependencies:    []composer.Upgrade{{Name: "guzzlehttp/guzzle", From: "7.8.0", To: "7.9.0"}},
+	}
+
+	got, err := renderNotes(tmpl, notes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "## What's Changed\n\n" +
+		"### Breaking Changes\n\n" +
+		"- **api:** remove v1 routes by Grace\n" +
+		"  - clients must use /v2\n\n" +
+		"### Fixes\n\n" +
+		"- refresh tokens by @ada in #14\n\n" +
+		"## New Contributors\n\n" +
+		"- @ada made their first contribution in #14\n\n" +
+		"## Dependency Updates\n\n" +
+		"- guzzlehttp/guzzle 7.8.0 → 7.9.0\n\n" +
+		"**Full Changelog**: https://github.com/acme/app/compare/v1.0.0...v1.1.0"
+	if got != expected {
+		t.Fatalf("unexpected notes:\n%s", got)
+	}
+
+	got, err = renderNotes(tmpl, Notes{Notice: "No commits found", CompareURL: "https://github.com/acme/app/compare/v1.0.0...v1.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "## What's Changed\n\nNo commits found\n\n**Full Changelog**: https://github.com/acme/app/compare/v1.0.0...v1.0.1" {
+		t.Fatalf("unexpected empty notes:\n%s", got)
+	}
+}
+
+func TestNotesTemplate_ReadsRepositoryTemplate(t *testing.T) {
+	dir := t.TempDir()
+	text := "{{.NewTag}} ({{.Type}})\n{{range .Commits}}* {{.ShortHash}} {{.Subject}} ({{.Date}})\n{{end}}{{range .Rules}}{{.Severity}}: {{.File}} {{.Reason}}\n{{end}}"
+	if err := os.WriteFile(filepath.Join(dir, "notes.tmpl"), []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &shared.Config{BaseDir: dir, Project: shared.Project{Notes: shared.NotesSettings{Template: "notes.tmpl"}}}
+	tmpl, err := notesTemplate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notes := Notes{
+		NewTag:  "v2.0.0",
+		Type:    "major",
+		Commits: []gitops.Commit{{Hash: "0123456789abcdef", Subject: "Drop PHP 8.1", Date: "2026-03-02T10:00:00+01:00"}},
+		Rules:   []shared.Rule{{File: "composer.json", Severity: "major", Reason: "raised minimum PHP version from 8.1 to 8.2"}},
+	}
+	got, err := renderNotes(tmpl, notes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "v2.0.0 (major)\n* 0123456 Drop PHP 8.1 (2026-03-02T10:00:00+01:00)\nmajor: composer.json raised minimum PHP version from 8.1 to 8.2\n"
+	if got != expected {
+		t.Fatalf("unexpected notes:\n%s", got)
+	}
+}
+
+func TestNotesTemplate_ReportsInvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.tmpl"), []byte("{{range .Sections}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &shared.Config{BaseDir: dir, Project: shared.Project{Notes: shared.NotesSettings{Template: "notes.tmpl"}}}
+	if _, err := notesTemplate(cfg); err == nil {
+		t.Fatalf("expected a parse error")
+	}
+}
@@ -0,0 +1,106 @@
+package releasetype
+
+import (
+	"path"
+	"strings"
+
+	"releaser/tool/gitops"
+	"releaser/tool/shared"
+)
+
+// diffRange identifies the repository and the two refs an analyzer compares.
+type diffRange struct {
+	Dir  string
+	From string
+	To   string
+	// Root is the monorepo component directory the analyzed paths are
+	// relative to; empty for the whole repository.
+	Root    string
+	Project shared.Project
+}
+
+// analyzer inspects the changed files it matches and reports the release
+// signals and per-file rules it derives from them. The registry is internal
+// to the package: analyzers build on its signal and rule helpers.
+type analyzer interface {
+	Name() string
+	Match(file string) bool
+	Analyze(r diffRange, files []string) *releaseSignals
+}
+
+var registry []analyzer
+
+// register adds an analyzer to the set consulted by Detect. Analyzers
+// register themselves from an init function.
+func register(a analyzer) {
+	registry = append(registry, a)
+}
+
+// analyzers returns the registered analyzers in registration order.
+func analyzers() []analyzer {
+	return append([]analyzer{}, registry...)
+}
+
+// AnalyzerNames lists the analyzers that components can select in
+// .releaser.json.
+func AnalyzerNames() []string {
+	names := make([]string, 0, len(registry))
+	for _, a := range registry {
+		names = append(names, a.Name())
+	}
+	return names
+}
+
+func newDiffRange(cfg *shared.Config) diffRange {
+	return diffRange{
+		Dir:     cfg.BaseDir,
+		From:    cfg.OldTag,
+		To:      "HEAD",
+		Root:    cfg.ComponentPath(),
+		Project: cfg.Project,
+	}
+}
+
+// readFiles returns the contents of file at both ends of the range. A file
+// that does not exist at one end is returned as an empty string.
+func (r diffRange) readFiles(file string) (string, string, error) {
+	oldSrc, _, err := r.fileAtRef(r.From, file)
+	if err != nil {
+		return "", "", err
+	}
+	newSrc, _, err := r.fileAtRef(r.To, file)
+	if err != nil {
+		return "", "", err
+	}
+	return oldSrc, newSrc, nil
+}
+
+// fileAtRef reads a file of the analyzed tree at ref. The boolean is false
+// when the file does not exist there.
+func (r diffRange) fileAtRef(ref, file string) (string, bool, error) {
+	return gitops.FileAtRef(r.Dir, ref, path.Join(r.Root, file))
+}
+
+// listFilesAtRef lists the entries of dir at ref, relative to Root.
+func (r diffRange) listFilesAtRef(ref, dir string) ([]string, error) {
+	files, err := gitops.ListFilesAtRef(r.Dir, ref, path.Join(r.Root, dir))
+	if err != nil || r.Root == "" {
+		return files, err
+	}
+	for i, file := range files {
+		files[i] = strings.TrimPrefix(file, r.Root+"/")
+	}
+	return files, nil
+}
+
+func (s *releaseSignals) merge(other *releaseSignals) {
+	if other == nil {
+		return
+	}
+	s.major = s.major || other.major
+	s.minor = s.minor || other.minor
+	s.globalRules = append(s.globalRules, other.globalRules...)
+	for file, rules := range other.fileRules {
+		s.fileRules[file] = append(s.fileRules[file], rules...)
+	}
+}
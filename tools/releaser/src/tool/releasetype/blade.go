@@ -0,0 +1,272 @@
+package releasetype
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"releaser/tool/output"
+)
+
+const bladeComponentDir = "resources/views/components/"
+
+// bladeReservedVariables are available in every component view and are not
+// part of its contract.
+var bladeReservedVariables = map[string]bool{
+	"slot":       true,
+	"attributes": true,
+	"component":  true,
+	"loop":       true,
+	"errors":     true,
+	"app":        true,
+	"this":       true,
+	"__env":      true,
+	"__data":     true,
+}
+
+var (
+	bladeVariablePattern = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+	bladeAssignPattern   = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)\s*=[^=>]`)
+	bladeLoopPattern     = regexp.MustCompile(`@(?:foreach|forelse)\s*\(.*?\bas\s+(?:\$([A-Za-z_][A-Za-z0-9_]*)\s*=>\s*)?\$([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+func init() {
+	register(bladeAnalyzer{})
+}
+
+// bladeAnalyzer treats anonymous components under resources/views/components
+// as a public API: their props and slots form the contract, everything else
+// in a view is markup.
+type bladeAnalyzer struct{}
+
+// bladeProp is one entry of @props([...]). Props listed without a default
+// are required.
+type bladeProp struct {
+	name       string
+	required   bool
+	defaultVal string
+}
+
+type bladeComponent struct {
+	props    map[string]bladeProp
+	slots    map[string]bool
+	hasProps bool
+}
+
+func (bladeAnalyzer) Name() string {
+	return "blade"
+}
+
+func (bladeAnalyzer) Match(file string) bool {
+	return isViewFile(file)
+}
+
+func (bladeAnalyzer) Analyze(r diffRange, files []string) *releaseSignals {
+	signals := newReleaseSignals()
+	for _, file := range files {
+		output.VeryVerbose("Analyzing view file: " + file)
+		oldSrc, newSrc, err := r.readFiles(file)
+		if err != nil {
+			output.Warn("Failed to read " + file + ": " + err.Error())
+			continue
+		}
+		classifyView(file, oldSrc, newSrc, signals)
+	}
+	return signals
+}
+
+func isViewFile(file string) bool {
+	return strings.HasPrefix(file, "resources/views/")
+}
+
+// bladeComponentName maps resources/views/components/forms/input.blade.php
+// to "x-forms.input"; an index view names its directory.
+func bladeComponentName(file string) (string, bool) {
+	if !strings.HasPrefix(file, bladeComponentDir) || !strings.HasSuffix(file, ".blade.php") {
+		return "", false
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(file, bladeComponentDir), ".blade.php")
+	if strings.HasSuffix(name, "/index") {
+		name = strings.TrimSuffix(name, "/index")
+	}
+	return "x-" + strings.ReplaceAll(name, "/", "."), true
+}
+
+// classifyView reports removed components, props and slots and added
+// required props as major, added components, optional props and slots as
+// minor, and any other view change as patch.
+func classifyView(file, oldSrc, newSrc string, signals *releaseSignals) {
+	name, isComponent := bladeComponentName(file)
+	if !isComponent {
+		markPatchForFile(signals, file, "markup changed", "")
+		return
+	}
+	switch {
+	case strings.TrimSpace(newSrc) == "":
+		markMajorForFile(signals, file, "removed component "+name, "")
+		return
+	case strings.TrimSpace(oldSrc) == "":
+		markMinorForFile(signals, file, "added component "+name, bladePropsSnippet(parseBladeComponent(newSrc), "+ "))
+		return
+	}
+
+	oldComponent := parseBladeComponent(oldSrc)
+	newComponent := parseBladeComponent(newSrc)
+	found := false
+
+	for _, prop := range unionKeys(oldComponent.props, newComponent.props) {
+		oldProp, hadOld := oldComponent.props[prop]
+		newProp, hasNew := newComponent.props[prop]
+		switch {
+		case !hasNew:
+			markMajorForFile(signals, file, "removed prop "+prop+" from "+name, "- "+oldProp.String())
+		case !hadOld && newProp.required:
+			markMajorForFile(signals, file, "added required prop "+prop+" to "+name, "+ "+newProp.String())
+		case !hadOld:
+			markMinorForFile(signals, file, "added optional prop "+prop+" to "+name, "+ "+newProp.String())
+		case !oldProp.required && newProp.required:
+			markMajorForFile(signals, file, "made prop "+prop+" of "+name+" required", "- "+oldProp.String()+"\n+ "+newProp.String())
+		case oldProp.required && !newProp.required:
+			markMinorForFile(signals, file, "made prop "+prop+" of "+name+" optional", "- "+oldProp.String()+"\n+ "+newProp.String())
+		case oldProp.defaultVal != newProp.defaultVal:
+			markPatchForFile(signals, file, "changed default of prop "+prop+" of "+name, "- "+oldProp.String()+"\n+ "+newProp.String())
+		default:
+			continue
+		}
+		found = true
+	}
+
+	for _, slot := range unionKeys(oldComponent.slots, newComponent.slots) {
+		_, wasProp := oldComponent.props[slot]
+		_, isProp := newComponent.props[slot]
+		switch {
+		case wasProp || isProp:
+			continue
+		case !newComponent.slots[slot]:
+			markMajorForFile(signals, file, "removed slot "+slot+" from "+name, "- {{ $"+slot+" }}")
+		case !oldComponent.slots[slot]:
+			markMinorForFile(signals, file, "added slot "+slot+" to "+name, "+ {{ $"+slot+" }}")
+		default:
+			continue
+		}
+		found = true
+	}
+
+	if !found {
+		markPatchForFile(signals, file, "markup changed", "")
+	}
+}
+
+// parseBladeComponent reads the @props directive of a component view and
+// collects its named slots: variables the view echoes or checks that are
+// neither props, @aware parent data, reserved names nor assigned in the view
+// itself.
+func parseBladeComponent(src string) bladeComponent {
+	c := bladeComponent{props: make(map[string]bladeProp), slots: make(map[string]bool)}
+	local := make(map[string]bool)
+	body := src
+	if start, end, inner, ok := bladeDirectiveArgs(body, "@aware"); ok {
+		body = body[:start] + body[end:]
+		for _, entry := range parsePHPValue(tokenizePHP("<?php " + inner)).entries {
+			if entry.hasKey {
+				local[entry.key] = true
+			} else if entry.value.isString {
+				local[entry.value.str] = true
+			}
+		}
+	}
+	if start, end, inner, ok := bladeDirectiveArgs(body, "@props"); ok {
+		c.hasProps = true
+		body = body[:start] + body[end:]
+		value := parsePHPValue(tokenizePHP("<?php " + inner))
+		for _, entry := range value.entries {
+			if entry.hasKey {
+				c.props[entry.key] = bladeProp{name: entry.key, defaultVal: entry.value.raw}
+			} else if entry.value.isString {
+				c.props[entry.value.str] = bladeProp{name: entry.value.str, required: true}
+			}
+		}
+	}
+
+	for _, match := range bladeAssignPattern.FindAllStringSubmatch(body, -1) {
+		local[match[1]] = true
+	}
+	for _, match := range bladeLoopPattern.FindAllStringSubmatch(body, -1) {
+		local[match[1]] = true
+		local[match[2]] = true
+	}
+	for _, match := range bladeVariablePattern.FindAllStringSubmatch(body, -1) {
+		name := match[1]
+		if bladeReservedVariables[name] || local[name] {
+			continue
+		}
+		if _, isProp := c.props[name]; isProp {
+			continue
+		}
+		c.slots[name] = true
+	}
+	return c
+}
+
+// bladeDirectiveArgs locates a directive such as `@props(...)` and returns
+// its byte range and the source between the parentheses.
+func bladeDirectiveArgs(src, directive string) (int, int, string, bool) {
+	start := strings.Index(src, directive+"(")
+	if start < 0 {
+		start = strings.Index(src, directive+" (")
+	}
+	if start < 0 {
+		return 0, 0, "", false
+	}
+	open := strings.IndexByte(src[start:], '(')
+	if open < 0 {
+		return 0, 0, "", false
+	}
+	open += start
+	depth := 0
+	var quote byte
+	for i := open; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+			if depth == 0 {
+				return start, i + 1, src[open+1 : i], true
+			}
+		}
+	}
+	return 0, 0, "", false
+}
+
+func (p bladeProp) String() string {
+	if p.required {
+		return "'" + p.name + "'"
+	}
+	return "'" + p.name + "' => " + p.defaultVal
+}
+
+func bladePropsSnippet(c bladeComponent, prefix string) string {
+	if !c.hasProps {
+		return ""
+	}
+	names := make([]string, 0, len(c.props))
+	for name := range c.props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, c.props[name].String())
+	}
+	return prefix + "@props([" + strings.Join(parts, ", ") + "])"
+}
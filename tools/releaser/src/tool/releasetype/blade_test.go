@@ -0,0 +1,84 @@
+package releasetype
+
+import "testing"
+
+func TestClassifyView_DiffsComponentContract(t *testing.T) {
+	oldSrc := `@props(['label', 'type' => 'button', 'size' => 'md', 'icon' => null])
+
+<button {{ $attributes->merge(['type' => $type]) }} class="btn-{{ $size }}">
+    @isset($icon) <x-icon :name="$icon" /> @endisset
+    {{ $prefix }}
+    {{ $label }}
+    {{ $slot }}
+</button>
+`
+	newSrc := `@props([
+    'label' => null,
+    'variant',
+    'type' => 'submit',
+    'icon' => null,
+    'loading' => false,
+])
+
+@php($classes = 'btn btn-' . $variant)
+
+<button {{ $attributes->merge(['type' => $type, 'class' => $classes]) }}>
+    @foreach ($badges as $badge) <span>{{ $badge }}</span> @endforeach
+    {{ $leading }}
+    {{ $label }}
+    {{ $slot }}
+</button>
+`
+	s := newReleaseSignals()
+	classifyView("resources/views/components/forms/button.blade.php", oldSrc, newSrc, s)
+
+	expected := map[string]string{
+		"removed prop size from x-forms.button":          "major",
+		"added required prop variant to x-forms.button":  "major",
+		"added optional prop loading to x-forms.button":  "minor",
+		"made prop label of x-forms.button optional":     "minor",
+		"changed default of prop type of x-forms.button": "patch",
+		"removed slot prefix from x-forms.button":        "major",
+		"added slot leading to x-forms.button":           "minor",
+		"added slot badges to x-forms.button":            "minor",
+	}
+	rules := s.fileRules["resources/views/components/forms/button.blade.php"]
+	if len(rules) != len(expected) {
+		t.Fatalf("expected %d rules, got %+v", len(expected), rules)
+	}
+	for _, rule := range rules {
+		if expected[rule.reason] != rule.severity {
+			t.Fatalf("unexpected rule %+v", rule)
+		}
+	}
+}
+
+func TestClassifyView_MarkupAndLifecycle(t *testing.T) {
+	s := newReleaseSignals()
+	classifyView("resources/views/welcome.blade.php", "<h1>Hi</h1>", "<h1>Hello</h1>", s)
+	classifyView("resources/views/components/card/index.blade.php", "<div>{{ $slot }}</div>", "<section>{{ $slot }}</section>", s)
+	if s.major || s.minor {
+		t.Fatalf("expected markup edits to be patch, got %+v", s.fileRules)
+	}
+
+	classifyView("resources/views/components/alert.blade.php", "@props(['type'])\n<div>{{ $slot }}</div>", "", s)
+	rules := s.fileRules["resources/views/components/alert.blade.php"]
+	if len(rules) != 1 || rules[0].reason != "removed component x-alert" || rules[0].severity != "major" {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+}
+
+func TestBladeComponentName(t *testing.T) {
+	for file, expected := range map[string]string{
+		"resources/views/components/alert.blade.php":       "x-alert",
+		"resources/views/components/forms/input.blade.php": "x-forms.input",
+		"resources/views/components/card/index.blade.php":  "x-card",
+	} {
+		if got, ok := bladeComponentName(file); !ok || got != expected {
+			t.Fatalf("bladeComponentName(%q) = %q, expected %q", file, got, expected)
+		}
+	}
+	if _, ok := bladeComponentName("resources/views/welcome.blade.php"); ok {
+		t.Fatalf("expected non-component view to be rejected")
+	}
+}
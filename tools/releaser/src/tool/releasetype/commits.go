@@ -0,0 +1,71 @@
+package releasetype
+
+import (
+	"strconv"
+
+	"releaser/tool/conventional"
+	"releaser/tool/gitops"
+	"releaser/tool/output"
+	"releaser/tool/shared"
+)
+
+// commitSignals derives release signals from the Conventional Commits in the
+// release range and reports how many commits followed the convention.
+func commitSignals(cfg *shared.Config) (*releaseSignals, int) {
+	commits, err := gitops.Commits(cfg.BaseDir, cfg.OldTag+"..HEAD", cfg.Pathspec()...)
+	if err != nil {
+		output.Warn("Failed to read commit log: " + err.Error())
+		return newReleaseSignals(), 0
+	}
+	signals, count := classifyCommits(commits, cfg.Project.Commits.Types)
+	output.Verbose("Conventional commits found: " + strconv.Itoa(count) + " of " + strconv.Itoa(len(commits)))
+	return signals, count
+}
+
+// classifyCommits marks breaking commits as major and features as minor.
+// Other conventional commits only count towards the total. types are the
+// configured extra commit types.
+func classifyCommits(commits []gitops.Commit, types []string) (*releaseSignals, int) {
+	signals := newReleaseSignals()
+	count := 0
+	for _, commit := range commits {
+		c, ok := conventional.Parse(commit.Subject, commit.Body, types...)
+		if !ok {
+			continue
+		}
+		count++
+		message := "commit " + commit.ShortHash() + " " + c.Header()
+		switch c.Bump() {
+		case "major":
+			if c.BreakingNote != "" {
+				message += " — " + c.BreakingNote
+			}
+			markMajor(signals, message)
+		case "minor":
+			markMinor(signals, message)
+		}
+	}
+	return signals, count
+}
+
+// combineSignals merges the diff and commit signals. All rules are kept for
+// display; precedence decides which side sets the release type.
+func combineSignals(precedence string, diff, commits *releaseSignals, conventionalCount int) *releaseSignals {
+	combined := newReleaseSignals()
+	combined.merge(diff)
+	combined.merge(commits)
+
+	switch precedence {
+	case shared.PrecedenceCommits:
+		if conventionalCount > 0 {
+			output.Verbose("Commit signals take precedence over diff heuristics")
+			combined.major, combined.minor = commits.major, commits.minor
+		}
+	case shared.PrecedenceDiff:
+		if diff.major || diff.minor {
+			output.Verbose("Diff heuristics take precedence over commit signals")
+			combined.major, combined.minor = diff.major, diff.minor
+		}
+	}
+	return combined
+}
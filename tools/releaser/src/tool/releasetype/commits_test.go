@@ -0,0 +1,52 @@
+package releasetype
+
+import (
+	"testing"
+
+	"releaser/tool/gitops"
+	"releaser/tool/shared"
+)
+
+func TestClassifyCommits(t *testing.T) {
+	signals, count := classifyCommits([]gitops.Commit{
+		{Hash: "a1b2c3d4e5", Subject: "feat(ui): add dark mode"},
+		{Hash: "b2c3d4e5f6", Subject: "fix: typo"},
+		{Hash: "c3d4e5f6a7", Subject: "WIP"},
+		{Hash: "d4e5f6a7b8", Subject: "WIP: half of the export"},
+		{Hash: "e5f6a7b8c9", Subject: "deps: bump guzzle"},
+	}, []string{"deps"})
+	if count != 3 {
+		t.Fatalf("expected 3 conventional commits, got %d", count)
+	}
+	if signals.major || !signals.minor {
+		t.Fatalf("expected minor only, got major=%v minor=%v", signals.major, signals.minor)
+	}
+	if len(signals.globalRules) != 1 {
+		t.Fatalf("expected one rule, got %+v", signals.globalRules)
+	}
+}
+
+func TestCombineSignals_Precedence(t *testing.T) {
+	diff := newReleaseSignals()
+	diff.minor = true
+	commits := newReleaseSignals()
+	commits.major = true
+
+	tests := []struct {
+		precedence string
+		count      int
+		major      bool
+		minor      bool
+	}{
+		{precedence: shared.PrecedenceMax, count: 1, major: true, minor: true},
+		{precedence: shared.PrecedenceCommits, count: 1, major: true, minor: false},
+		{precedence: shared.PrecedenceCommits, count: 0, major: true, minor: true},
+		{precedence: shared.PrecedenceDiff, count: 1, major: false, minor: true},
+	}
+	for _, tt := range tests {
+		combined := combineSignals(tt.precedence, diff, commits, tt.count)
+		if combined.major != tt.major || combined.minor != tt.minor {
+			t.Fatalf("precedence %s (count %d): got major=%v minor=%v", tt.precedence, tt.count, combined.major, combined.minor)
+		}
+	}
+}
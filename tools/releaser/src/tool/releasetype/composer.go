@@ -0,0 +1,206 @@
+package releasetype
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"releaser/tool/composer"
+	"releaser/tool/output"
+)
+
+func init() {
+	register(composerAnalyzer{})
+}
+
+// composerAnalyzer diffs the requirements, autoload namespaces and
+// conflict/replace sections of composer.json, and summarizes lock upgrades.
+type composerAnalyzer struct{}
+
+func (composerAnalyzer) Name() string {
+	return "composer"
+}
+
+func (composerAnalyzer) Match(file string) bool {
+	if strings.HasPrefix(file, "vendor/") || strings.Contains(file, "/vendor/") {
+		return false
+	}
+	base := path.Base(file)
+	return base == "composer.json" || base == "composer.lock"
+}
+
+func (composerAnalyzer) Analyze(r diffRange, files []string) *releaseSignals {
+	signals := newReleaseSignals()
+	for _, file := range files {
+		output.VeryVerbose("Analyzing composer file: " + file)
+		oldSrc, newSrc, err := r.readFiles(file)
+		if err != nil {
+			output.Warn("Failed to read " + file + ": " + err.Error())
+			continue
+		}
+		if path.Base(file) == "composer.lock" {
+			err = classifyComposerLock(file, oldSrc, newSrc, signals)
+		} else {
+			err = classifyComposerManifest(file, oldSrc, newSrc, signals)
+		}
+		if err != nil {
+			output.Warn("Failed to parse " + file + ": " + err.Error())
+		}
+	}
+	return signals
+}
+
+func classifyComposerManifest(file, oldSrc, newSrc string, signals *releaseSignals) error {
+	switch {
+	case strings.TrimSpace(newSrc) == "":
+		markMajorForFile(signals, file, "removed composer manifest", "")
+		return nil
+	case strings.TrimSpace(oldSrc) == "":
+		markMinorForFile(signals, file, "added composer manifest", "")
+		return nil
+	}
+	oldManifest, err := composer.ParseManifest([]byte(oldSrc))
+	if err != nil {
+		return err
+	}
+	newManifest, err := composer.ParseManifest([]byte(newSrc))
+	if err != nil {
+		return err
+	}
+	diffComposerManifests(file, oldManifest, newManifest, signals)
+	return nil
+}
+
+// diffComposerManifests reports raised platform minimums, raised dependency
+// majors, removed namespaces, new conflicts and removed replacements as
+// major; new runtime requirements and namespaces as minor; dev-only and
+// other constraint changes as patch.
+func diffComposerManifests(file string, oldManifest, newManifest *composer.Manifest, signals *releaseSignals) {
+	for _, name := range unionKeys(oldManifest.Require, newManifest.Require) {
+		oldConstraint, hadOld := oldManifest.Require[name]
+		newConstraint, hasNew := newManifest.Require[name]
+		snippet := composerSnippet(name, oldConstraint, newConstraint, hadOld, hasNew)
+		switch {
+		case !hasNew:
+			markPatchForFile(signals, file, "dropped requirement "+name, snippet)
+		case !hadOld:
+			markMinorForFile(signals, file, "added requirement "+name+" "+newConstraint, snippet)
+		case oldConstraint != newConstraint:
+			classifyConstraintChange(file, name, oldConstraint, newConstraint, snippet, signals)
+		}
+	}
+
+	for _, name := range unionKeys(oldManifest.RequireDev, newManifest.RequireDev) {
+		oldConstraint, hadOld := oldManifest.RequireDev[name]
+		newConstraint, hasNew := newManifest.RequireDev[name]
+		if hadOld && hasNew && oldConstraint == newConstraint {
+			continue
+		}
+		markPatchForFile(signals, file, "changed dev requirement "+name, composerSnippet(name, oldConstraint, newConstraint, hadOld, hasNew))
+	}
+
+	oldNamespaces := oldManifest.Autoload.Namespaces()
+	newNamespaces := newManifest.Autoload.Namespaces()
+	for _, namespace := range unionKeys(oldNamespaces, newNamespaces) {
+		oldPath, hadOld := oldNamespaces[namespace]
+		newPath, hasNew := newNamespaces[namespace]
+		snippet := composerSnippet(namespace, oldPath, newPath, hadOld, hasNew)
+		switch {
+		case !hasNew:
+			markMajorForFile(signals, file, "removed autoload namespace "+namespace, snippet)
+		case !hadOld:
+			markMinorForFile(signals, file, "added autoload namespace "+namespace, snippet)
+		case oldPath != newPath:
+			markPatchForFile(signals, file, "moved autoload namespace "+namespace, snippet)
+		}
+	}
+
+	for _, name := range unionKeys(oldManifest.Conflict, newManifest.Conflict) {
+		oldConstraint, hadOld := oldManifest.Conflict[name]
+		newConstraint, hasNew := newManifest.Conflict[name]
+		snippet := composerSnippet(name, oldConstraint, newConstraint, hadOld, hasNew)
+		switch {
+		case !hasNew:
+			markPatchForFile(signals, file, "removed conflict with "+name, snippet)
+		case !hadOld:
+			markMajorForFile(signals, file, "added conflict with "+name+" "+newConstraint, snippet)
+		case oldConstraint != newConstraint:
+			markMajorForFile(signals, file, "changed conflict with "+name, snippet)
+		}
+	}
+
+	for _, name := range unionKeys(oldManifest.Replace, newManifest.Replace) {
+		oldConstraint, hadOld := oldManifest.Replace[name]
+		newConstraint, hasNew := newManifest.Replace[name]
+		snippet := composerSnippet(name, oldConstraint, newConstraint, hadOld, hasNew)
+		switch {
+		case !hasNew:
+			markMajorForFile(signals, file, "no longer replaces "+name, snippet)
+		case !hadOld:
+			markMinorForFile(signals, file, "now replaces "+name, snippet)
+		case oldConstraint != newConstraint:
+			markPatchForFile(signals, file, "changed replacement of "+name, snippet)
+		}
+	}
+}
+
+func classifyConstraintChange(file, name, oldConstraint, newConstraint, snippet string, signals *releaseSignals) {
+	oldMin, oldOK := composer.MinimumVersion(oldConstraint)
+	newMin, newOK := composer.MinimumVersion(newConstraint)
+	switch {
+	case !oldOK || !newOK:
+	case composer.IsPlatform(name) && newMin.Compare(oldMin) > 0:
+		label := name
+		if name == "php" {
+			label = "PHP"
+		}
+		markMajorForFile(signals, file, fmt.Sprintf("raised minimum %s version from %s to %s", label, oldMin, newMin), snippet)
+		return
+	case newMin.Major() > oldMin.Major():
+		markMajorForFile(signals, file, fmt.Sprintf("raised %s minimum major from %d to %d", name, oldMin.Major(), newMin.Major()), snippet)
+		return
+	}
+	markPatchForFile(signals, file, "changed constraint of "+name, snippet)
+}
+
+func classifyComposerLock(file, oldSrc, newSrc string, signals *releaseSignals) error {
+	if strings.TrimSpace(oldSrc) == "" || strings.TrimSpace(newSrc) == "" {
+		markPatchForFile(signals, file, "lock file added or removed", "")
+		return nil
+	}
+	oldLock, err := composer.ParseLock([]byte(oldSrc))
+	if err != nil {
+		return err
+	}
+	newLock, err := composer.ParseLock([]byte(newSrc))
+	if err != nil {
+		return err
+	}
+
+	upgrades := composer.Upgrades(oldLock, newLock)
+	if len(upgrades) == 0 {
+		markPatchForFile(signals, file, "no locked versions changed", "")
+		return nil
+	}
+	var lines []string
+	for i, upgrade := range upgrades {
+		if i == 10 {
+			lines = append(lines, fmt.Sprintf("... %d more", len(upgrades)-i))
+			break
+		}
+		lines = append(lines, upgrade.String())
+	}
+	markPatchForFile(signals, file, fmt.Sprintf("updated %d locked packages", len(upgrades)), strings.Join(lines, "\n"))
+	return nil
+}
+
+func composerSnippet(name, oldValue, newValue string, hadOld, hasNew bool) string {
+	var lines []string
+	if hadOld {
+		lines = append(lines, fmt.Sprintf("- %q: %q", name, oldValue))
+	}
+	if hasNew {
+		lines = append(lines, fmt.Sprintf("+ %q: %q", name, newValue))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,87 @@
+package releasetype
+
+import (
+	"testing"
+)
+
+func TestDiffComposerManifests_ClassifiesChanges(t *testing.T) {
+	oldSrc := `{
+	"require": {
+		"php": "^8.1",
+		"laravel/framework": "^10.0",
+		"guzzlehttp/guzzle": "^7.5",
+		"spatie/once": "^3.0"
+	},
+	"require-dev": { "phpunit/phpunit": "^10.0" },
+	"autoload": { "psr-4": { "Acme\\Core\\": "src/", "Acme\\Legacy\\": "legacy/" } },
+	"replace": { "acme/core-legacy": "self.version" }
+}`
+	newSrc := `{
+	"require": {
+		"php": "^8.2",
+		"laravel/framework": "^11.0",
+		"guzzlehttp/guzzle": "^7.8",
+		"symfony/yaml": "^7.0"
+	},
+	"require-dev": { "phpunit/phpunit": "^11.0" },
+	"autoload": { "psr-4": { "Acme\\Core\\": "src/", "Acme\\Support\\": "support/" } },
+	"conflict": { "doctrine/dbal": "<3.0" }
+}`
+
+	s := newReleaseSignals()
+	if err := classifyComposerManifest("composer.json", oldSrc, newSrc, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"raised minimum PHP version from 8.1 to 8.2":           "major",
+		"raised laravel/framework minimum major from 10 to 11": "major",
+		"changed constraint of guzzlehttp/guzzle":              "patch",
+		"dropped requirement spatie/once":                      "patch",
+		"added requirement symfony/yaml ^7.0":                  "minor",
+		"changed dev requirement phpunit/phpunit":              "patch",
+		`removed autoload namespace Acme\Legacy\`:              "major",
+		`added autoload namespace Acme\Support\`:               "minor",
+		"added conflict with doctrine/dbal <3.0":               "major",
+		"no longer replaces acme/core-legacy":                  "major",
+	}
+	rules := s.fileRules["composer.json"]
+	if len(rules) != len(expected) {
+		t.Fatalf("expected %d rules, got %+v", len(expected), rules)
+	}
+	for _, rule := range rules {
+		if expected[rule.reason] != rule.severity {
+			t.Fatalf("unexpected rule %+v", rule)
+		}
+	}
+}
+
+func TestDiffComposerManifests_DevOnlyChangesArePatch(t *testing.T) {
+	oldSrc := `{"require": {"php": "^8.2"}, "require-dev": {"pestphp/pest": "^2.0"}}`
+	newSrc := `{"require": {"php": "^8.2"}, "require-dev": {"pestphp/pest": "^3.0", "larastan/larastan": "^2.9"}}`
+
+	s := newReleaseSignals()
+	if err := classifyComposerManifest("composer.json", oldSrc, newSrc, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.major || s.minor {
+		t.Fatalf("expected dev-only changes to be patch, got %+v", s.fileRules)
+	}
+	if len(s.fileRules["composer.json"]) != 2 {
+		t.Fatalf("expected 2 patch rules, got %+v", s.fileRules)
+	}
+}
+
+func TestClassifyComposerLock_SummarizesUpgrades(t *testing.T) {
+	oldSrc := `{"packages": [{"name": "laravel/framework", "version": "v11.0.0"}], "packages-dev": []}`
+	newSrc := `{"packages": [{"name": "laravel/framework", "version": "v11.1.0"}], "packages-dev": []}`
+
+	s := newReleaseSignals()
+	if err := classifyComposerLock("composer.lock", oldSrc, newSrc, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rules := s.fileRules["composer.lock"]
+	if len(rules) != 1 || rules[0].severity != "patch" || rules[0].snippet != "laravel/framework v11.0.0 → v11.1.0" {
+		t.Fatalf("unexpected lock rules %+v", rules)
+	}
+}
@@ -0,0 +1,178 @@
+package releasetype
+
+import (
+	"strings"
+
+	"releaser/tool/output"
+)
+
+func init() {
+	register(configAnalyzer{})
+}
+
+// configAnalyzer evaluates the array returned by each Laravel config file
+// and compares the key paths it defines.
+type configAnalyzer struct{}
+
+// configEntry is one key path of a config array. Arrays with string keys are
+// walked into; any other value, lists included, is a leaf.
+type configEntry struct {
+	nested bool
+	value  string
+}
+
+func (configAnalyzer) Name() string {
+	return "config"
+}
+
+func (configAnalyzer) Match(file string) bool {
+	return isConfigFile(file)
+}
+
+func (configAnalyzer) Analyze(r diffRange, files []string) *releaseSignals {
+	signals := newReleaseSignals()
+	for _, file := range files {
+		output.VeryVerbose("Analyzing config file: " + file)
+		oldSrc, newSrc, err := r.readFiles(file)
+		if err != nil {
+			output.Warn("Failed to read " + file + ": " + err.Error())
+			continue
+		}
+		classifyConfig(file, oldSrc, newSrc, signals)
+	}
+	return signals
+}
+
+func isConfigFile(file string) bool {
+	return strings.HasPrefix(file, "config/") && strings.HasSuffix(file, ".php")
+}
+
+// classifyConfig reports removed keys and keys whose value turned from an
+// array into a scalar (or back) as major, new keys as minor and changed
+// values as patch. Keys below a removed or added parent are not repeated.
+func classifyConfig(file, oldSrc, newSrc string, signals *releaseSignals) {
+	prefix := configKeyPrefix(file)
+	oldKeys := parseConfigKeys(prefix, oldSrc)
+	newKeys := parseConfigKeys(prefix, newSrc)
+
+	found := false
+	reported := make(map[string]bool)
+	coveredBy := func(key string) bool {
+		for parent := key; strings.Contains(parent, "."); {
+			parent = parent[:strings.LastIndex(parent, ".")]
+			if reported[parent] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, key := range unionKeys(oldKeys, newKeys) {
+		oldEntry, hadOld := oldKeys[key]
+		newEntry, hasNew := newKeys[key]
+		switch {
+		case !hasNew:
+			if !coveredBy(key) {
+				markMajorForFile(signals, file, "removed key "+key, oldEntry.snippet(key, "- "))
+			}
+			reported[key] = true
+		case !hadOld:
+			if !coveredBy(key) {
+				markMinorForFile(signals, file, "added key "+key, newEntry.snippet(key, "+ "))
+			}
+			reported[key] = true
+		case oldEntry.nested != newEntry.nested:
+			markMajorForFile(signals, file, "changed structure of key "+key, oldEntry.snippet(key, "- ")+"\n"+newEntry.snippet(key, "+ "))
+			reported[key] = true
+		case !oldEntry.nested && oldEntry.value != newEntry.value:
+			markPatchForFile(signals, file, "changed value of key "+key, oldEntry.snippet(key, "- ")+"\n"+newEntry.snippet(key, "+ "))
+		default:
+			continue
+		}
+		found = true
+	}
+	if !found {
+		markPatchForFile(signals, file, "no key changes detected", "")
+	}
+}
+
+// configKeyPrefix turns config/services.php into "services" and
+// config/packages/acme.php into "packages.acme", matching config() lookups.
+func configKeyPrefix(file string) string {
+	name := strings.TrimSuffix(strings.TrimPrefix(file, "config/"), ".php")
+	return strings.ReplaceAll(name, "/", ".")
+}
+
+// parseConfigKeys flattens the array returned by a config file into key
+// paths. A file without a literal array return has no keys.
+func parseConfigKeys(prefix, src string) map[string]configEntry {
+	keys := make(map[string]configEntry)
+	if strings.TrimSpace(src) == "" {
+		return keys
+	}
+	toks := tokenizePHP(src)
+	depth := 0
+	for i, tok := range toks {
+		switch tok.text {
+		case "(", "[", "{", "#[":
+			depth++
+		case ")", "]", "}":
+			depth--
+		}
+		if depth != 0 || tok.kind != phpTokIdent || !strings.EqualFold(tok.text, "return") {
+			continue
+		}
+		end := i + 1
+		for ; end < len(toks); end++ {
+			if toks[end].text == "(" || toks[end].text == "[" || toks[end].text == "{" {
+				depth++
+			} else if toks[end].text == ")" || toks[end].text == "]" || toks[end].text == "}" {
+				depth--
+			} else if toks[end].text == ";" && depth == 0 {
+				break
+			}
+		}
+		value := parsePHPValue(toks[i+1 : end])
+		if value.isArray {
+			flattenConfigValue(prefix, value, keys)
+		}
+		return keys
+	}
+	return keys
+}
+
+func flattenConfigValue(prefix string, value *phpValue, keys map[string]configEntry) {
+	for _, entry := range value.entries {
+		if !entry.hasKey {
+			continue
+		}
+		key := prefix + "." + entry.key
+		if isConfigMap(entry.value) {
+			keys[key] = configEntry{nested: true}
+			flattenConfigValue(key, entry.value, keys)
+			continue
+		}
+		keys[key] = configEntry{value: entry.value.raw}
+	}
+}
+
+// isConfigMap reports whether v is a non-empty array whose entries all have
+// keys. Lists are compared as a whole value.
+func isConfigMap(v *phpValue) bool {
+	if v == nil || !v.isArray || len(v.entries) == 0 {
+		return false
+	}
+	for _, entry := range v.entries {
+		if !entry.hasKey {
+			return false
+		}
+	}
+	return true
+}
+
+func (e configEntry) snippet(key, prefix string) string {
+	if e.nested {
+		return prefix + key + " => [...]"
+	}
+	return prefix + renderSnippetCode(key+" => "+e.value, 140)
+}
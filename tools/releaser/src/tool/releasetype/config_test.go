@@ -0,0 +1,78 @@
+package releasetype
+
+import "testing"
+
+func TestClassifyConfig_DiffsKeyPaths(t *testing.T) {
+	oldSrc := `<?php
+
+return [
+    'default' => env('CACHE_STORE', 'file'),
+    'stores' => [
+        'redis' => [
+            'driver' => 'redis',
+            'connection' => 'cache',
+        ],
+        'legacy' => [
+            'driver' => 'memcached',
+            'servers' => [['host' => '127.0.0.1']],
+        ],
+    ],
+    'prefix' => 'app_cache',
+    'limits' => 10,
+];
+`
+	newSrc := `<?php
+
+return [
+    'default' => env('CACHE_STORE', 'redis'),
+    'stores' => [
+        'redis' => [
+            'driver' => 'redis',
+            'connection' => 'cache',
+            'lock_connection' => 'default',
+        ],
+    ],
+    'key_prefix' => 'app_cache',
+    'limits' => ['per_minute' => 10],
+];
+`
+	s := newReleaseSignals()
+	classifyConfig("config/cache.php", oldSrc, newSrc, s)
+
+	expected := map[string]string{
+		"changed value of key cache.default":           "patch",
+		"removed key cache.stores.legacy":              "major",
+		"added key cache.stores.redis.lock_connection": "minor",
+		"removed key cache.prefix":                     "major",
+		"added key cache.key_prefix":                   "minor",
+		"changed structure of key cache.limits":        "major",
+	}
+	rules := s.fileRules["config/cache.php"]
+	if len(rules) != len(expected) {
+		t.Fatalf("expected %d rules, got %+v", len(expected), rules)
+	}
+	for _, rule := range rules {
+		if expected[rule.reason] != rule.severity {
+			t.Fatalf("unexpected rule %+v", rule)
+		}
+	}
+}
+
+func TestClassifyConfig_UnchangedKeysArePatch(t *testing.T) {
+	src := "<?php\n\nreturn ['name' => env('APP_NAME', 'Laravel')];\n"
+	s := newReleaseSignals()
+	classifyConfig("config/app.php", src, src+"\n// trailing comment\n", s)
+	if s.major || s.minor {
+		t.Fatalf("expected no major/minor signals, got %+v", s.fileRules)
+	}
+	rules := s.fileRules["config/app.php"]
+	if len(rules) != 1 || rules[0].reason != "no key changes detected" {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+}
+
+func TestConfigKeyPrefix(t *testing.T) {
+	if got := configKeyPrefix("config/packages/acme.php"); got != "packages.acme" {
+		t.Fatalf("unexpected prefix %q", got)
+	}
+}
@@ -7,32 +7,12 @@ import (
 	"releaser/tool/shared"
 )
 
-func applyFileCategorySignals(buckets changeBuckets, signals *releaseSignals) {
-	output.Verbose("Applying non-PHP category signals")
-	if len(buckets.composerFiles) > 0 {
-		markPatch(signals, "composer.json/lock changed")
-		output.VeryVerboseList("Composer files", buckets.composerFiles, 10)
-	}
-	if len(buckets.views) > 0 {
-		markMinor(signals, fmt.Sprintf("views changed (%d)", len(buckets.views)))
-		output.VeryVerboseList("View files", buckets.views, 10)
-	}
-	if len(buckets.migrations) > 0 {
-		markMinor(signals, fmt.Sprintf("migrations changed (%d)", len(buckets.migrations)))
-		output.VeryVerboseList("Migration files", buckets.migrations, 10)
-	}
-	if len(buckets.configs) > 0 {
-		markMinor(signals, fmt.Sprintf("configs changed (%d)", len(buckets.configs)))
-		output.VeryVerboseList("Config files", buckets.configs, 10)
-	}
-}
-
 func applyFinalDecision(cfg *shared.Config, buckets changeBuckets, signals *releaseSignals) {
 	output.Blank()
 	output.Verbose("Applying final release-type decision")
 	signals.emitRules()
 
-	if buckets.hasOnlyDocs() {
+	if buckets.hasOnlyDocs() && !signals.major && !signals.minor {
 		files := buckets.docsFiles()
 		output.Info(fmt.Sprintf("🧪 Only docs changed (%d files) → %s", len(files), output.SemverLabel("patch")))
 		output.VeryVerboseList("Doc files", files, 20)
@@ -76,3 +56,7 @@ func markMinorForFile(signals *releaseSignals, file, reason, snippet string) {
 	signals.minor = true
 	signals.addFileRule(file, "minor", reason, snippet)
 }
+
+func markPatchForFile(signals *releaseSignals, file, reason, snippet string) {
+	signals.addFileRule(file, "patch", reason, snippet)
+}
@@ -1,6 +1,8 @@
 package releasetype
 
 import (
+	"strconv"
+
 	"releaser/tool/gitops"
 	"releaser/tool/output"
 	"releaser/tool/shared"
@@ -9,15 +11,20 @@ import (
 func Detect(cfg *shared.Config) error {
 	output.Info("Detecting release type from git diff since " + cfg.OldTag + "...")
 	output.Verbose("Release type diff range: " + cfg.OldTag + "..HEAD")
-	_, _ = gitops.Run(cfg.BaseDir, "fetch", "--tags")
+	_ = gitops.FetchTags(cfg)
 
-	changedFilesRaw, err := gitops.Run(cfg.BaseDir, "diff", "--name-only", cfg.OldTag+"..HEAD")
+	args := []string{"diff", "--name-only", cfg.OldTag + "..HEAD"}
+	if root := cfg.ComponentPath(); root != "" {
+		output.Verbose("Limiting detection to component " + cfg.Component.Name + " in " + root)
+		args = append(args, "--relative="+root, "--", root)
+	}
+	changedFilesRaw, err := gitops.Run(cfg.BaseDir, args...)
 	if err != nil {
 		output.Warn("Failed to run git diff for changed files")
 		return err
 	}
 
-	buckets, empty := collectChangedFiles(changedFilesRaw)
+	buckets, empty := collectChangedFiles(changedFilesRaw, enabledAnalyzers(cfg))
 	if empty {
 		output.Info("No code changes detected → " + output.SemverLabel("patch"))
 		cfg.Type = "patch"
@@ -26,29 +33,47 @@ func Detect(cfg *shared.Config) error {
 	output.Verbose("Changed files by category: " + buckets.summary())
 	logBucketDetails(buckets)
 
-	signals := newReleaseSignals()
-	analyzePHPChanges(cfg, buckets.phpFiles, signals)
-	applyFileCategorySignals(buckets, signals)
+	diffSignals := newReleaseSignals()
+	runAnalyzers(newDiffRange(cfg), buckets, diffSignals)
+	commits, conventionalCount := commitSignals(cfg)
+	signals := combineSignals(cfg.Project.Commits.Precedence, diffSignals, commits, conventionalCount)
 	output.Verbose("Signals before final decision: major=" + boolString(signals.major) + " minor=" + boolString(signals.minor))
 	applyFinalDecision(cfg, buckets, signals)
+	cfg.Rules = signals.rules()
 	output.Verbose("Final detected release type: " + cfg.Type)
 	return nil
 }
 
-func analyzePHPChanges(cfg *shared.Config, phpFiles []string, signals *releaseSignals) {
-	for _, file := range phpFiles {
-		output.VeryVerbose("Analyzing PHP file: " + file)
-		analyzePHPFile(cfg, file, signals)
+// enabledAnalyzers returns the analyzers configured for the component being
+// released, or all of them.
+func enabledAnalyzers(cfg *shared.Config) []analyzer {
+	if cfg.Component == nil || len(cfg.Component.Analyzers) == 0 {
+		return analyzers()
+	}
+	var enabled []analyzer
+	for _, analyzer := range analyzers() {
+		for _, name := range cfg.Component.Analyzers {
+			if analyzer.Name() == name {
+				enabled = append(enabled, analyzer)
+			}
+		}
+	}
+	return enabled
+}
+
+func runAnalyzers(r diffRange, buckets changeBuckets, signals *releaseSignals) {
+	for _, analyzer := range buckets.analyzers {
+		files := buckets.analyzed[analyzer.Name()]
+		output.Verbose("Running " + analyzer.Name() + " analyzer on " + strconv.Itoa(len(files)) + " file(s)")
+		signals.merge(analyzer.Analyze(r, files))
 	}
 }
 
 func logBucketDetails(buckets changeBuckets) {
-	output.VeryVerboseList("PHP files", buckets.phpFiles, 10)
-	output.VeryVerboseList("Migration files", buckets.migrations, 10)
+	for _, analyzer := range buckets.analyzers {
+		output.VeryVerboseList("Files for "+analyzer.Name()+" analyzer", buckets.analyzed[analyzer.Name()], 10)
+	}
 	output.VeryVerboseList("Doc files", buckets.docs, 10)
-	output.VeryVerboseList("Config files", buckets.configs, 10)
-	output.VeryVerboseList("View files", buckets.views, 10)
-	output.VeryVerboseList("Composer files", buckets.composerFiles, 10)
 }
 
 func boolString(v bool) string {
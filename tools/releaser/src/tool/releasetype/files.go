@@ -7,7 +7,9 @@ import (
 	"releaser/tool/output"
 )
 
-func collectChangedFiles(raw string) (changeBuckets, bool) {
+// collectChangedFiles assigns every changed file to the analyzers that match
+// it, and collects documentation files for the docs-only shortcut.
+func collectChangedFiles(raw string, analyzers []analyzer) (changeBuckets, bool) {
 	files := trimNonEmptyLines(raw)
 	if len(files) == 0 {
 		return changeBuckets{}, true
@@ -15,26 +17,21 @@ func collectChangedFiles(raw string) (changeBuckets, bool) {
 	output.Verbose("Changed file count: " + strconv.Itoa(len(files)))
 	output.VeryVerboseList("Changed files", files, 20)
 
-	buckets := changeBuckets{}
+	buckets := changeBuckets{analyzed: make(map[string][]string)}
 	for _, file := range files {
-		switch {
-		case strings.HasSuffix(file, ".php") && !strings.HasPrefix(file, "tests/"):
-			buckets.phpFiles = append(buckets.phpFiles, file)
+		for _, analyzer := range analyzers {
+			if !analyzer.Match(file) {
+				continue
+			}
+			name := analyzer.Name()
+			if _, seen := buckets.analyzed[name]; !seen {
+				buckets.analyzers = append(buckets.analyzers, analyzer)
+			}
+			buckets.analyzed[name] = append(buckets.analyzed[name], file)
 		}
 
-		switch {
-		case strings.HasPrefix(file, "database/migrations/"):
-			buckets.migrations = append(buckets.migrations, file)
-		case isDocLikeFile(file):
+		if isDocLikeFile(file) {
 			buckets.docs = append(buckets.docs, file)
-		case strings.HasPrefix(file, "config/"):
-			buckets.configs = append(buckets.configs, file)
-		case strings.HasPrefix(file, "resources/views"):
-			buckets.views = append(buckets.views, file)
-		}
-
-		if file == "composer.json" || file == "composer.lock" {
-			buckets.composerFiles = append(buckets.composerFiles, file)
 		}
 	}
 
@@ -3,24 +3,24 @@ package releasetype
 import "testing"
 
 func TestCollectChangedFiles_Empty(t *testing.T) {
-	buckets, empty := collectChangedFiles("\n\n")
+	buckets, empty := collectChangedFiles("\n\n", analyzers())
 	if !empty {
 		t.Fatalf("expected empty=true")
 	}
-	if len(buckets.phpFiles) != 0 {
-		t.Fatalf("expected no php files")
+	if len(buckets.analyzed) != 0 {
+		t.Fatalf("expected no analyzed files")
 	}
 }
 
 func TestCollectChangedFiles_ExcludesTestsFromPHPHeuristics(t *testing.T) {
 	raw := "tests/Unit/FooTest.php\napp/Services/Foo.php\ndocs/readme.md\ndatabase/migrations/2026_01_01_create_users.php\nconfig/app.php\nresources/views/home.blade.php\ncomposer.json\n"
 
-	buckets, empty := collectChangedFiles(raw)
+	buckets, empty := collectChangedFiles(raw, analyzers())
 	if empty {
 		t.Fatalf("expected empty=false")
 	}
 
-	for _, f := range buckets.phpFiles {
+	for _, f := range buckets.analyzed["php"] {
 		if len(f) >= 6 && f[:6] == "tests/" {
 			t.Fatalf("tests file must not be part of php heuristics: %s", f)
 		}
@@ -29,17 +29,17 @@ func TestCollectChangedFiles_ExcludesTestsFromPHPHeuristics(t *testing.T) {
 	if len(buckets.docs) != 1 {
 		t.Fatalf("expected 1 docs file, got %d", len(buckets.docs))
 	}
-	if len(buckets.migrations) != 1 {
-		t.Fatalf("expected 1 migration file, got %d", len(buckets.migrations))
+	if len(buckets.analyzed["migrations"]) != 1 {
+		t.Fatalf("expected 1 migration file, got %d", len(buckets.analyzed["migrations"]))
 	}
-	if len(buckets.configs) != 1 {
-		t.Fatalf("expected 1 config file, got %d", len(buckets.configs))
+	if len(buckets.analyzed["config"]) != 1 {
+		t.Fatalf("expected 1 config file, got %d", len(buckets.analyzed["config"]))
 	}
-	if len(buckets.views) != 1 {
-		t.Fatalf("expected 1 view file, got %d", len(buckets.views))
+	if len(buckets.analyzed["blade"]) != 1 {
+		t.Fatalf("expected 1 view file, got %d", len(buckets.analyzed["blade"]))
 	}
-	if len(buckets.composerFiles) != 1 {
-		t.Fatalf("expected 1 composer file, got %d", len(buckets.composerFiles))
+	if len(buckets.analyzed["composer"]) != 1 {
+		t.Fatalf("expected 1 composer file, got %d", len(buckets.analyzed["composer"]))
 	}
 }
 
@@ -47,7 +47,24 @@ func TestChangeBuckets_HasOnlyDocs(t *testing.T) {
 	if !(changeBuckets{docs: []string{"README.md"}}).hasOnlyDocs() {
 		t.Fatalf("expected hasOnlyDocs for docs-only changes")
 	}
-	if (changeBuckets{phpFiles: []string{"app/Foo.php"}, docs: []string{"README.md"}}).hasOnlyDocs() {
+	if (changeBuckets{analyzed: map[string][]string{"php": {"app/Foo.php"}}, docs: []string{"README.md"}}).hasOnlyDocs() {
 		t.Fatalf("expected hasOnlyDocs=false when php files exist")
 	}
 }
+
+func TestCollectChangedFiles_RoutesFilesToMatchingAnalyzers(t *testing.T) {
+	raw := "app/Services/Foo.php\nroutes/api.php\ndatabase/migrations/2026_01_01_create_users.php\n"
+
+	buckets, _ := collectChangedFiles(raw, analyzers())
+
+	for name, expected := range map[string]string{
+		"php":        "app/Services/Foo.php",
+		"routes":     "routes/api.php",
+		"migrations": "database/migrations/2026_01_01_create_users.php",
+	} {
+		files := buckets.analyzed[name]
+		if len(files) != 1 || files[0] != expected {
+			t.Fatalf("expected %s analyzer to get %s, got %v", name, expected, files)
+		}
+	}
+}
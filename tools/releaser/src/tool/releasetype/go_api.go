@@ -0,0 +1,386 @@
+package releasetype
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"releaser/tool/output"
+)
+
+func init() {
+	register(goAnalyzer{})
+}
+
+// goAnalyzer type-checks every changed package at both refs and compares
+// their exported API.
+type goAnalyzer struct{}
+
+type goSymbol struct {
+	kind      string
+	name      string
+	signature string
+	file      string
+	line      int
+}
+
+// goAPI maps a stable symbol key such as "method Client.Do" to its symbol.
+type goAPI map[string]goSymbol
+
+func (goAnalyzer) Name() string {
+	return "go"
+}
+
+func (goAnalyzer) Match(file string) bool {
+	if !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+		return false
+	}
+	for _, segment := range strings.Split(path.Dir(file), "/") {
+		if segment == "vendor" || segment == "testdata" || segment == "internal" {
+			return false
+		}
+	}
+	return true
+}
+
+func (goAnalyzer) Analyze(r diffRange, files []string) *releaseSignals {
+	signals := newReleaseSignals()
+	fset := token.NewFileSet()
+	std := importer.ForCompiler(fset, "source", nil)
+	oldTree := newGoTree(fset, std, gitGoSource(r, r.From))
+	newTree := newGoTree(fset, std, gitGoSource(r, r.To))
+
+	for _, dir := range goPackageDirs(files) {
+		output.VeryVerbose("Analyzing Go package: " + dir)
+		oldAPI, oldName, err := oldTree.api(dir)
+		if err != nil {
+			output.Warn("Failed to load Go package " + dir + " at " + r.From + ": " + err.Error())
+			continue
+		}
+		newAPI, newName, err := newTree.api(dir)
+		if err != nil {
+			output.Warn("Failed to load Go package " + dir + " at " + r.To + ": " + err.Error())
+			continue
+		}
+		if oldName == "main" || newName == "main" {
+			output.VeryVerbose("Skipping main package " + dir)
+			continue
+		}
+		diffGoAPI(dir, oldAPI, newAPI, signals)
+	}
+	return signals
+}
+
+func goPackageDirs(files []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, file := range files {
+		dir := path.Dir(file)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// diffGoAPI reports removed or changed exported identifiers and added
+// interface methods as major, and new exported identifiers as minor.
+func diffGoAPI(dir string, oldAPI, newAPI goAPI, signals *releaseSignals) {
+	switch {
+	case len(oldAPI) == 0 && len(newAPI) == 0:
+		return
+	case len(newAPI) == 0:
+		markMajorForFile(signals, dir+"/", "removed package "+dir, "")
+		return
+	case len(oldAPI) == 0:
+		markMinorForFile(signals, dir+"/", "added package "+dir, "")
+		return
+	}
+
+	for _, key := range unionKeys(oldAPI, newAPI) {
+		oldSym, hadOld := oldAPI[key]
+		newSym, hasNew := newAPI[key]
+		switch {
+		case !hasNew:
+			markMajorForFile(signals, oldSym.file, "removed "+oldSym.kind+" "+oldSym.name, "- "+oldSym.signature)
+		case !hadOld && newSym.kind == "interface method":
+			markMajorForFile(signals, newSym.file, "added "+newSym.kind+" "+newSym.name, "+ "+newSym.signature)
+		case !hadOld:
+			markMinorForFile(signals, newSym.file, "added "+newSym.kind+" "+newSym.name, "+ "+newSym.signature)
+		case oldSym.signature != newSym.signature:
+			markMajorForFile(signals, newSym.file, "changed "+newSym.kind+" "+newSym.name, "- "+oldSym.signature+"\n+ "+newSym.signature)
+		}
+	}
+}
+
+// goSource reads Go files from one version of the repository.
+type goSource struct {
+	readFile func(file string) (string, bool, error)
+	listDir  func(dir string) ([]string, error)
+}
+
+func gitGoSource(r diffRange, ref string) goSource {
+	return goSource{
+		readFile: func(file string) (string, bool, error) {
+			return r.fileAtRef(ref, file)
+		},
+		listDir: func(path string) ([]string, error) {
+			return r.listFilesAtRef(ref, path)
+		},
+	}
+}
+
+type goModule struct {
+	root string
+	path string
+}
+
+// goTree type-checks packages of one repository version. Imports from the
+// same module are resolved from that version, the standard library from
+// GOROOT sources; anything else is left unresolved.
+type goTree struct {
+	fset     *token.FileSet
+	std      types.Importer
+	src      goSource
+	modules  map[string]*goModule
+	packages map[string]*types.Package
+	loading  map[string]bool
+}
+
+func newGoTree(fset *token.FileSet, std types.Importer, src goSource) *goTree {
+	return &goTree{
+		fset:     fset,
+		std:      std,
+		src:      src,
+		modules:  make(map[string]*goModule),
+		packages: make(map[string]*types.Package),
+		loading:  make(map[string]bool),
+	}
+}
+
+func (t *goTree) api(dir string) (goAPI, string, error) {
+	pkg, err := t.loadDir(dir)
+	if err != nil || pkg == nil {
+		return goAPI{}, "", err
+	}
+	return exportedGoAPI(t.fset, pkg), pkg.Name(), nil
+}
+
+func (t *goTree) Import(importPath string) (*types.Package, error) {
+	if pkg, ok := t.packages[importPath]; ok {
+		return pkg, nil
+	}
+	for _, mod := range t.modules {
+		if importPath != mod.path && !strings.HasPrefix(importPath, mod.path+"/") {
+			continue
+		}
+		dir := path.Join(mod.root, strings.TrimPrefix(strings.TrimPrefix(importPath, mod.path), "/"))
+		pkg, err := t.loadDir(dir)
+		if err == nil && pkg == nil {
+			err = fmt.Errorf("no Go files in %s", dir)
+		}
+		return pkg, err
+	}
+	if first, _, _ := strings.Cut(importPath, "/"); !strings.Contains(first, ".") {
+		return t.std.Import(importPath)
+	}
+	return nil, errors.New("external package " + importPath + " is not resolved")
+}
+
+func (t *goTree) loadDir(dir string) (*types.Package, error) {
+	mod, err := t.module(dir)
+	if err != nil {
+		return nil, err
+	}
+	importPath := dir
+	if mod != nil {
+		importPath = path.Join(mod.path, strings.TrimPrefix(strings.TrimPrefix(dir, mod.root), "/"))
+	}
+	if pkg, ok := t.packages[importPath]; ok {
+		return pkg, nil
+	}
+	if t.loading[importPath] {
+		return nil, errors.New("import cycle through " + importPath)
+	}
+	t.loading[importPath] = true
+	defer delete(t.loading, importPath)
+
+	files, err := t.parseDir(dir)
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	conf := types.Config{
+		Importer: t,
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check(importPath, t.fset, files, nil)
+	t.packages[importPath] = pkg
+	return pkg, nil
+}
+
+func (t *goTree) parseDir(dir string) ([]*ast.File, error) {
+	entries, err := t.src.listDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make(map[string]string)
+	ctx := build.Default
+	ctx.CgoEnabled = false
+	ctx.JoinPath = path.Join
+	ctx.OpenFile = func(file string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(contents[file])), nil
+	}
+
+	var files []*ast.File
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry, ".go") || strings.HasSuffix(entry, "_test.go") {
+			continue
+		}
+		src, ok, err := t.src.readFile(entry)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		contents[entry] = src
+		if match, err := ctx.MatchFile(dir, path.Base(entry)); err != nil || !match {
+			continue
+		}
+		file, err := parser.ParseFile(t.fset, entry, src, parser.SkipObjectResolution)
+		if err != nil {
+			output.VeryVerbose("Skipping unparsable Go file " + entry + ": " + err.Error())
+			continue
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// module finds the go.mod governing dir by walking up the tree.
+func (t *goTree) module(dir string) (*goModule, error) {
+	for current := dir; ; current = path.Dir(current) {
+		if mod, ok := t.modules[current]; ok {
+			return mod, nil
+		}
+		src, ok, err := t.src.readFile(path.Join(current, "go.mod"))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			mod := &goModule{root: current, path: goModulePath(src)}
+			t.modules[current] = mod
+			return mod, nil
+		}
+		if current == "." || current == "/" {
+			return nil, nil
+		}
+	}
+}
+
+func goModulePath(gomod string) string {
+	for _, line := range strings.Split(gomod, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// exportedGoAPI lists the exported package-level identifiers of pkg together
+// with the exported methods, struct fields and interface methods of its types.
+func exportedGoAPI(fset *token.FileSet, pkg *types.Package) goAPI {
+	api := goAPI{}
+	qualifier := func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+	add := func(kind, name, signature string, pos token.Pos) {
+		position := fset.Position(pos)
+		api[kind+" "+name] = goSymbol{kind: kind, name: name, signature: signature, file: position.Filename, line: position.Line}
+	}
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		switch obj := obj.(type) {
+		case *types.Func:
+			add("func", name, types.ObjectString(obj, qualifier), obj.Pos())
+		case *types.Var:
+			add("var", name, types.ObjectString(obj, qualifier), obj.Pos())
+		case *types.Const:
+			add("const", name, "const "+name+" "+types.TypeString(obj.Type(), qualifier), obj.Pos())
+		case *types.TypeName:
+			add("type", name, goTypeDeclString(obj, qualifier), obj.Pos())
+			addGoTypeMembers(obj, qualifier, add)
+		}
+	}
+	return api
+}
+
+func goTypeDeclString(obj *types.TypeName, qualifier types.Qualifier) string {
+	if obj.IsAlias() {
+		return "type " + obj.Name() + " = " + types.TypeString(obj.Type(), qualifier)
+	}
+	switch underlying := obj.Type().Underlying().(type) {
+	case *types.Struct:
+		return "type " + obj.Name() + " struct"
+	case *types.Interface:
+		return "type " + obj.Name() + " interface"
+	default:
+		return "type " + obj.Name() + " " + types.TypeString(underlying, qualifier)
+	}
+}
+
+func addGoTypeMembers(obj *types.TypeName, qualifier types.Qualifier, add func(kind, name, signature string, pos token.Pos)) {
+	named, ok := obj.Type().(*types.Named)
+	if !ok || obj.IsAlias() {
+		return
+	}
+
+	for i := 0; i < named.NumMethods(); i++ {
+		method := named.Method(i)
+		if !method.Exported() {
+			continue
+		}
+		add("method", obj.Name()+"."+method.Name(), types.ObjectString(method, qualifier), method.Pos())
+	}
+
+	switch underlying := named.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < underlying.NumFields(); i++ {
+			field := underlying.Field(i)
+			if !field.Exported() {
+				continue
+			}
+			add("field", obj.Name()+"."+field.Name(), obj.Name()+"."+field.Name()+" "+types.TypeString(field.Type(), qualifier), field.Pos())
+		}
+	case *types.Interface:
+		for i := 0; i < underlying.NumMethods(); i++ {
+			method := underlying.Method(i)
+			if !method.Exported() {
+				continue
+			}
+			add("interface method", obj.Name()+"."+method.Name(), obj.Name()+"."+method.Name()+strings.TrimPrefix(types.TypeString(method.Type(), qualifier), "func"), method.Pos())
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package releasetype
+
+import (
+	"go/importer"
+	"go/token"
+	"path"
+	"sort"
+	"testing"
+)
+
+func memoryGoSource(files map[string]string) goSource {
+	return goSource{
+		readFile: func(file string) (string, bool, error) {
+			src, ok := files[file]
+			return src, ok, nil
+		},
+		listDir: func(dir string) ([]string, error) {
+			var out []string
+			for file := range files {
+				if path.Dir(file) == dir {
+					out = append(out, file)
+				}
+			}
+			sort.Strings(out)
+			return out, nil
+		},
+	}
+}
+
+func TestDiffGoAPI_ClassifiesExportedChanges(t *testing.T) {
+	oldFiles := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"lib/client.go": `package lib
+
+import "example.com/app/lib/model"
+
+type Client struct {
+	BaseURL string
+	Timeout int
+	secret  string
+}
+
+func (c *Client) Do(req model.Request) error { return nil }
+
+type Store interface {
+	Get(key string) (string, error)
+}
+
+func New(url string) *Client { return &Client{BaseURL: url} }
+
+func Legacy() {}
+`,
+		"lib/model/model.go": "package model\n\ntype Request struct{ Path string }\n",
+	}
+	newFiles := map[string]string{
+		"go.mod": oldFiles["go.mod"],
+		"lib/client.go": `package lib
+
+import "example.com/app/lib/model"
+
+type Client struct {
+	BaseURL string
+	Retries int
+}
+
+func (c *Client) Do(req model.Request, opts ...Option) error { return nil }
+
+type Option func(*Client)
+
+type Store interface {
+	Get(key string) (string, error)
+	Delete(key string) error
+}
+
+func New(url string) *Client { return &Client{BaseURL: url} }
+`,
+		"lib/model/model.go": oldFiles["lib/model/model.go"],
+	}
+
+	fset := token.NewFileSet()
+	std := importer.ForCompiler(fset, "source", nil)
+	oldAPI, _, err := newGoTree(fset, std, memoryGoSource(oldFiles)).api("lib")
+	if err != nil {
+		t.Fatalf("failed to load old API: %v", err)
+	}
+	newAPI, _, err := newGoTree(fset, std, memoryGoSource(newFiles)).api("lib")
+	if err != nil {
+		t.Fatalf("failed to load new API: %v", err)
+	}
+	if sym := oldAPI["method Client.Do"]; sym.signature != "func (*Client).Do(req model.Request) error" {
+		t.Fatalf("unexpected method signature %q", sym.signature)
+	}
+
+	s := newReleaseSignals()
+	diffGoAPI("lib", oldAPI, newAPI, s)
+
+	expected := map[string]string{
+		"removed field Client.Timeout":        "major",
+		"added field Client.Retries":          "minor",
+		"changed method Client.Do":            "major",
+		"added type Option":                   "minor",
+		"added interface method Store.Delete": "major",
+		"removed func Legacy":                 "major",
+	}
+	rules := s.fileRules["lib/client.go"]
+	if len(rules) != len(expected) {
+		t.Fatalf("expected %d rules, got %+v", len(expected), rules)
+	}
+	for _, rule := range rules {
+		if expected[rule.reason] != rule.severity {
+			t.Fatalf("unexpected rule %+v", rule)
+		}
+	}
+}
+
+func TestGoAnalyzer_MatchSkipsTestsAndInternalPackages(t *testing.T) {
+	a := goAnalyzer{}
+	for file, expected := range map[string]bool{
+		"pkg/client.go":          true,
+		"main.go":                true,
+		"pkg/client_test.go":     false,
+		"internal/auth/token.go": false,
+		"vendor/x/y.go":          false,
+		"README.md":              false,
+	} {
+		if got := a.Match(file); got != expected {
+			t.Fatalf("Match(%q) = %v, expected %v", file, got, expected)
+		}
+	}
+}
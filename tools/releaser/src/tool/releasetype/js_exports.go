@@ -0,0 +1,518 @@
+package releasetype
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"releaser/tool/output"
+)
+
+var jsSourceExtensions = []string{".ts", ".tsx", ".d.ts", ".js", ".mjs", ".cjs", ".jsx", ".mts", ".cts"}
+
+var jsStrippedExtensions = []string{".d.ts", ".d.mts", ".d.cts", ".ts", ".tsx", ".mts", ".cts", ".js", ".jsx", ".mjs", ".cjs"}
+
+// jsBuildDirs are output directories whose sources usually live in src/.
+var jsBuildDirs = map[string]bool{"dist": true, "lib": true, "build": true, "out": true, "esm": true, "cjs": true}
+
+// jsConditionRank orders export conditions by how likely their target is
+// to be the module that declares the public API.
+var jsConditionRank = map[string]int{"types": 0, "import": 1, "module": 2, "default": 3, "require": 4, "node": 5, "browser": 6}
+
+func init() {
+	register(jsAnalyzer{})
+}
+
+// jsAnalyzer compares the entry points declared in package.json and the
+// symbols exported from them for every npm package touched by the diff.
+// Packages marked private are not published and are skipped.
+type jsAnalyzer struct{}
+
+// jsSource reads a file from one version of the repository.
+type jsSource func(file string) (string, bool, error)
+
+type jsPackage struct {
+	root    string
+	name    string
+	private bool
+	fields  map[string]string
+	entries map[string][]string
+}
+
+type jsExportTarget struct {
+	subpath    string
+	conditions string
+	target     string
+}
+
+// jsAPI maps an entry subpath such as "." or "./utils" to its exports.
+type jsAPI map[string]map[string]jsSymbol
+
+func (jsAnalyzer) Name() string {
+	return "js"
+}
+
+func (jsAnalyzer) Match(file string) bool {
+	for _, segment := range strings.Split(path.Dir(file), "/") {
+		if segment == "node_modules" || segment == "vendor" || segment == "__tests__" || segment == "tests" {
+			return false
+		}
+	}
+	base := path.Base(file)
+	if base == "package.json" {
+		return true
+	}
+	if strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") {
+		return false
+	}
+	for _, ext := range jsSourceExtensions {
+		if strings.HasSuffix(base, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func (jsAnalyzer) Analyze(r diffRange, files []string) *releaseSignals {
+	signals := newReleaseSignals()
+	oldTree := newJSTree(gitJSSource(r, r.From))
+	newTree := newJSTree(gitJSSource(r, r.To))
+
+	for _, root := range jsPackageRoots(files, newTree, oldTree) {
+		oldPkg, err := oldTree.loadPackage(root)
+		if err != nil {
+			output.Warn("Failed to read " + path.Join(root, "package.json") + " at " + r.From + ": " + err.Error())
+			continue
+		}
+		newPkg, err := newTree.loadPackage(root)
+		if err != nil {
+			output.Warn("Failed to read " + path.Join(root, "package.json") + " at " + r.To + ": " + err.Error())
+			continue
+		}
+		diffJSPackage(root, oldTree, newTree, oldPkg, newPkg, signals)
+	}
+	return signals
+}
+
+func gitJSSource(r diffRange, ref string) jsSource {
+	return func(file string) (string, bool, error) {
+		return r.fileAtRef(ref, file)
+	}
+}
+
+// jsPackageRoots returns the directories of the nearest package.json of each
+// file, looking at the new version first and the old one for deleted files.
+func jsPackageRoots(files []string, trees ...*jsTree) []string {
+	seen := make(map[string]bool)
+	var roots []string
+	for _, file := range files {
+		for _, tree := range trees {
+			root, ok := tree.packageRoot(path.Dir(file))
+			if !ok {
+				continue
+			}
+			if !seen[root] {
+				seen[root] = true
+				roots = append(roots, root)
+			}
+			break
+		}
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// diffJSPackage reports removed entry points and exports as major, new ones
+// as minor and retargeted entry points as patch.
+func diffJSPackage(root string, oldTree, newTree *jsTree, oldPkg, newPkg *jsPackage, signals *releaseSignals) {
+	manifest := path.Join(root, "package.json")
+	switch {
+	case oldPkg == nil && newPkg == nil:
+		return
+	case (oldPkg == nil || oldPkg.private) && (newPkg == nil || newPkg.private):
+		output.VeryVerbose("Skipping private package " + root)
+		return
+	case newPkg == nil || newPkg.private:
+		markMajorForFile(signals, manifest, "removed package "+oldPkg.name, "")
+		return
+	case oldPkg == nil || oldPkg.private:
+		markMinorForFile(signals, manifest, "added package "+newPkg.name, "")
+		return
+	}
+
+	output.VeryVerbose("Analyzing npm package: " + newPkg.name)
+	for _, key := range unionKeys(oldPkg.fields, newPkg.fields) {
+		oldTarget, hadOld := oldPkg.fields[key]
+		newTarget, hasNew := newPkg.fields[key]
+		switch {
+		case !hasNew:
+			markMajorForFile(signals, manifest, "removed package entry "+key, fmt.Sprintf("- %s: %q", key, oldTarget))
+		case !hadOld:
+			markMinorForFile(signals, manifest, "added package entry "+key, fmt.Sprintf("+ %s: %q", key, newTarget))
+		case oldTarget != newTarget:
+			markPatchForFile(signals, manifest, "changed package entry "+key, fmt.Sprintf("- %s: %q\n+ %s: %q", key, oldTarget, key, newTarget))
+		}
+	}
+
+	oldAPI := oldTree.api(oldPkg)
+	newAPI := newTree.api(newPkg)
+	for _, subpath := range unionKeys(oldAPI, newAPI) {
+		oldExports, hadOld := oldAPI[subpath]
+		newExports, hasNew := newAPI[subpath]
+		if !hadOld || !hasNew {
+			output.VeryVerbose("Skipping exports of " + newPkg.name + " " + subpath + ": entry source not found at both refs")
+			continue
+		}
+		diffJSExports(subpath, oldTree, newTree, oldExports, newExports, signals)
+	}
+}
+
+func diffJSExports(subpath string, oldTree, newTree *jsTree, oldExports, newExports map[string]jsSymbol, signals *releaseSignals) {
+	from := ""
+	if subpath != "." {
+		from = " from " + subpath
+	}
+	for _, name := range unionKeys(oldExports, newExports) {
+		oldSym, hadOld := oldExports[name]
+		newSym, hasNew := newExports[name]
+		switch {
+		case !hasNew:
+			markMajorForFile(signals, oldSym.file, "removed export "+name+from, oldTree.snippet(oldSym, "- "))
+		case !hadOld:
+			markMinorForFile(signals, newSym.file, "added export "+name+from, newTree.snippet(newSym, "+ "))
+		case jsKindChanged(oldSym.kind, newSym.kind):
+			markMajorForFile(signals, newSym.file, "changed export "+name+from+" from "+oldSym.kind+" to "+newSym.kind, oldTree.snippet(oldSym, "- ")+"\n"+newTree.snippet(newSym, "+ "))
+		case len(oldSym.arities) > 0 && len(newSym.arities) > 0 && formatJSArities(oldSym.arities) != formatJSArities(newSym.arities):
+			markMajorForFile(signals, newSym.file, fmt.Sprintf("changed arity of %s%s from %s to %s", name, from, formatJSArities(oldSym.arities), formatJSArities(newSym.arities)), oldTree.snippet(oldSym, "- ")+"\n"+newTree.snippet(newSym, "+ "))
+		}
+	}
+}
+
+// jsKindChanged ignores "value", the kind of exports whose declaration could
+// not be resolved, and treats let/var/const bindings alike.
+func jsKindChanged(oldKind, newKind string) bool {
+	if oldKind == "value" || newKind == "value" {
+		return false
+	}
+	family := func(kind string) string {
+		if kind == "variable" {
+			return "const"
+		}
+		return kind
+	}
+	return family(oldKind) != family(newKind)
+}
+
+// jsTree resolves packages and modules of one repository version.
+type jsTree struct {
+	read    jsSource
+	files   map[string]*string
+	modules map[string]*jsModule
+}
+
+func newJSTree(read jsSource) *jsTree {
+	return &jsTree{
+		read:    read,
+		files:   make(map[string]*string),
+		modules: make(map[string]*jsModule),
+	}
+}
+
+func (t *jsTree) readFile(file string) (string, bool) {
+	if src, ok := t.files[file]; ok {
+		if src == nil {
+			return "", false
+		}
+		return *src, true
+	}
+	src, ok, err := t.read(file)
+	if err != nil || !ok {
+		if err != nil {
+			output.VeryVerbose("Failed to read " + file + ": " + err.Error())
+		}
+		t.files[file] = nil
+		return "", false
+	}
+	t.files[file] = &src
+	return src, true
+}
+
+func (t *jsTree) packageRoot(dir string) (string, bool) {
+	for current := dir; ; current = path.Dir(current) {
+		if _, ok := t.readFile(path.Join(current, "package.json")); ok {
+			return current, true
+		}
+		if current == "." || current == "/" {
+			return "", false
+		}
+	}
+}
+
+// loadPackage reads the package.json in root, returning nil when it does not
+// exist at this version.
+func (t *jsTree) loadPackage(root string) (*jsPackage, error) {
+	src, ok, err := t.read(path.Join(root, "package.json"))
+	if err != nil || !ok {
+		return nil, err
+	}
+	var manifest struct {
+		Name    string          `json:"name"`
+		Private bool            `json:"private"`
+		Main    string          `json:"main"`
+		Module  string          `json:"module"`
+		Types   string          `json:"types"`
+		Typings string          `json:"typings"`
+		Exports json.RawMessage `json:"exports"`
+	}
+	if err := json.Unmarshal([]byte(src), &manifest); err != nil {
+		return nil, err
+	}
+
+	pkg := &jsPackage{
+		root:    root,
+		name:    manifest.Name,
+		private: manifest.Private,
+		fields:  make(map[string]string),
+		entries: make(map[string][]string),
+	}
+	if pkg.name == "" {
+		pkg.name = root
+	}
+	for key, value := range map[string]string{"main": manifest.Main, "module": manifest.Module, "types": manifest.Types, "typings": manifest.Typings} {
+		if value != "" {
+			pkg.fields[key] = value
+		}
+	}
+
+	var exports any
+	if len(manifest.Exports) > 0 {
+		if err := json.Unmarshal(manifest.Exports, &exports); err != nil {
+			return nil, err
+		}
+	}
+	targets := flattenJSExports(exports)
+	sort.SliceStable(targets, func(i, j int) bool {
+		return jsConditionOrder(targets[i].conditions) < jsConditionOrder(targets[j].conditions)
+	})
+	for _, target := range targets {
+		key := fmt.Sprintf("exports[%q]", target.subpath)
+		if target.conditions != "" {
+			key += "." + target.conditions
+		}
+		pkg.fields[key] = target.target
+		if target.target != "null" && !strings.Contains(target.subpath, "*") {
+			pkg.entries[target.subpath] = append(pkg.entries[target.subpath], target.target)
+		}
+	}
+	if exports == nil {
+		for _, field := range []string{"types", "typings", "module", "main"} {
+			if target, ok := pkg.fields[field]; ok {
+				pkg.entries["."] = append(pkg.entries["."], target)
+			}
+		}
+		if len(pkg.entries["."]) == 0 {
+			pkg.entries["."] = []string{"index.js"}
+		}
+	}
+	return pkg, nil
+}
+
+// flattenJSExports lists the targets of an `exports` value, which may be a
+// single path, a map of subpaths or a (nested) map of conditions.
+func flattenJSExports(exports any) []jsExportTarget {
+	if subpaths, ok := exports.(map[string]any); ok {
+		isSubpathMap := false
+		for key := range subpaths {
+			if strings.HasPrefix(key, ".") {
+				isSubpathMap = true
+				break
+			}
+		}
+		if isSubpathMap {
+			var out []jsExportTarget
+			for _, key := range sortedJSKeys(subpaths) {
+				out = append(out, flattenJSConditions(key, "", subpaths[key])...)
+			}
+			return out
+		}
+	}
+	if exports == nil {
+		return nil
+	}
+	return flattenJSConditions(".", "", exports)
+}
+
+func flattenJSConditions(subpath, conditions string, value any) []jsExportTarget {
+	switch value := value.(type) {
+	case nil:
+		return []jsExportTarget{{subpath, conditions, "null"}}
+	case string:
+		return []jsExportTarget{{subpath, conditions, value}}
+	case []any:
+		var out []jsExportTarget
+		for _, item := range value {
+			out = append(out, flattenJSConditions(subpath, conditions, item)...)
+		}
+		return out
+	case map[string]any:
+		var out []jsExportTarget
+		for _, key := range sortedJSKeys(value) {
+			nested := key
+			if conditions != "" {
+				nested = conditions + "." + key
+			}
+			out = append(out, flattenJSConditions(subpath, nested, value[key])...)
+		}
+		return out
+	}
+	return nil
+}
+
+func jsConditionOrder(conditions string) int {
+	if conditions == "" {
+		return len(jsConditionRank)
+	}
+	last := conditions[strings.LastIndex(conditions, ".")+1:]
+	if rank, ok := jsConditionRank[last]; ok {
+		return rank
+	}
+	return len(jsConditionRank) + 1
+}
+
+func sortedJSKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// api resolves every entry subpath of pkg to a source module and collects
+// its exports. Subpaths whose source cannot be found are left out.
+func (t *jsTree) api(pkg *jsPackage) jsAPI {
+	api := jsAPI{}
+	for subpath, targets := range pkg.entries {
+		for _, target := range targets {
+			file, ok := t.resolveEntry(pkg.root, target)
+			if !ok {
+				continue
+			}
+			api[subpath] = t.moduleExports(file, make(map[string]bool))
+			break
+		}
+	}
+	return api
+}
+
+// resolveEntry maps a package.json target to a committed source file. Build
+// output is usually not committed, so targets under dist/, lib/ and similar
+// directories are also looked up under src/ with TypeScript extensions.
+func (t *jsTree) resolveEntry(root, target string) (string, bool) {
+	rel := path.Clean(strings.TrimPrefix(target, "./"))
+	if file, ok := t.resolveSource(path.Join(root, rel)); ok {
+		return file, true
+	}
+	first, rest, found := strings.Cut(rel, "/")
+	if found && jsBuildDirs[first] {
+		return t.resolveSource(path.Join(root, "src", rest))
+	}
+	return "", false
+}
+
+func (t *jsTree) resolveModule(from, specifier string) (string, bool) {
+	if !strings.HasPrefix(specifier, ".") {
+		return "", false
+	}
+	return t.resolveSource(path.Join(path.Dir(from), specifier))
+}
+
+// resolveSource finds the file a module path refers to, trying the path as
+// is, with its extension swapped for a source one and as a directory index.
+func (t *jsTree) resolveSource(base string) (string, bool) {
+	stem := base
+	for _, ext := range jsStrippedExtensions {
+		if strings.HasSuffix(stem, ext) {
+			stem = strings.TrimSuffix(stem, ext)
+			break
+		}
+	}
+	candidates := []string{base}
+	for _, ext := range jsSourceExtensions {
+		candidates = append(candidates, stem+ext)
+	}
+	for _, ext := range jsSourceExtensions {
+		candidates = append(candidates, path.Join(base, "index"+ext))
+	}
+	for _, candidate := range candidates {
+		if _, ok := t.readFile(candidate); ok {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func (t *jsTree) module(file string) *jsModule {
+	if m, ok := t.modules[file]; ok {
+		return m
+	}
+	src, _ := t.readFile(file)
+	m := parseJSModule(file, src)
+	t.modules[file] = m
+	return m
+}
+
+// moduleExports returns the exports of file with `export ... from` clauses
+// expanded. Re-exports from other packages are kept by name only.
+func (t *jsTree) moduleExports(file string, visiting map[string]bool) map[string]jsSymbol {
+	if visiting[file] {
+		return nil
+	}
+	visiting[file] = true
+	defer delete(visiting, file)
+
+	m := t.module(file)
+	exports := m.resolvedExports()
+	for _, re := range m.reExports {
+		target, ok := t.resolveModule(file, re.specifier)
+		if !ok {
+			if re.names == nil {
+				name := "* from " + re.specifier
+				exports[name] = jsSymbol{name: name, kind: "re-export", file: file, line: re.line}
+			}
+			for exported := range re.names {
+				exports[exported] = jsSymbol{name: exported, kind: "value", file: file, line: re.line}
+			}
+			continue
+		}
+
+		nested := t.moduleExports(target, visiting)
+		if re.names == nil {
+			for name, sym := range nested {
+				if _, own := exports[name]; !own && name != "default" {
+					exports[name] = sym
+				}
+			}
+			continue
+		}
+		for exported, imported := range re.names {
+			sym, ok := nested[imported]
+			if !ok {
+				sym = jsSymbol{kind: "value", file: file, line: re.line}
+			}
+			sym.name = exported
+			exports[exported] = sym
+		}
+	}
+	return exports
+}
+
+func (t *jsTree) snippet(sym jsSymbol, prefix string) string {
+	src, ok := t.readFile(sym.file)
+	if !ok {
+		return ""
+	}
+	return snippetBlock(strings.Split(src, "\n"), sym.line-1, 1, prefix)
+}
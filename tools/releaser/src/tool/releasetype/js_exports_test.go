@@ -0,0 +1,217 @@
+package releasetype
+
+import (
+	"fmt"
+	"testing"
+)
+
+func memoryJSSource(files map[string]string) jsSource {
+	return func(file string) (string, bool, error) {
+		src, ok := files[file]
+		return src, ok, nil
+	}
+}
+
+func TestParseJSModule_CollectsExports(t *testing.T) {
+	src := `import { helper } from './helper'
+
+// export function commented(a) {}
+export function format(value, options = {}) {
+	return helper(value, options)
+}
+
+export function parse(this: Parser, input: string, opts?: Map<string, number>): Result;
+export function parse(this: Parser, input: string): Result;
+
+export const slugify = (text: string, sep = '-') => text.replace(/[^a-z]+/g, sep)
+export const VERSION = '1.0.0', retries = 3
+
+export async function load<T>(url: string): Promise<T> { return fetch(url) }
+
+export interface Options { locale: string }
+export type Formatter = (value: string) => string
+export const enum Mode { Fast, Safe }
+export abstract class Base {}
+
+function internal(a, b, c) {}
+export { internal as compute, helper }
+export * from './shared'
+export * as icons from './icons'
+export default format
+`
+	m := parseJSModule("src/index.ts", src)
+	exports := m.resolvedExports()
+
+	expected := map[string]string{
+		"format":    "function 2",
+		"parse":     "function 1 or 2",
+		"slugify":   "function 2",
+		"VERSION":   "const ",
+		"retries":   "const ",
+		"load":      "function 1",
+		"Options":   "interface ",
+		"Formatter": "type ",
+		"Mode":      "enum ",
+		"Base":      "class ",
+		"compute":   "function 3",
+		"helper":    "value ",
+		"icons":     "namespace ",
+		"default":   "function 2",
+	}
+	if len(exports) != len(expected) {
+		t.Fatalf("expected %d exports, got %+v", len(expected), exports)
+	}
+	for name, want := range expected {
+		sym, ok := exports[name]
+		if !ok {
+			t.Fatalf("missing export %s", name)
+		}
+		if got := sym.kind + " " + formatJSArities(sym.arities); got != want {
+			t.Fatalf("export %s = %q, expected %q", name, got, want)
+		}
+	}
+	if len(m.reExports) != 1 || m.reExports[0].specifier != "./shared" {
+		t.Fatalf("unexpected re-exports %+v", m.reExports)
+	}
+}
+
+func TestParseJSModule_CommonJS(t *testing.T) {
+	src := `function connect(host, port) {}
+module.exports = { connect, close: function (conn) {}, VERSION: '2' }
+module.exports.retry = (fn, times) => fn()
+exports.noop = () => {}
+`
+	exports := parseJSModule("index.js", src).resolvedExports()
+	expected := map[string]string{
+		"connect": "function 2",
+		"close":   "function 1",
+		"VERSION": "value ",
+		"retry":   "function 2",
+		"noop":    "function 0",
+	}
+	for name, want := range expected {
+		sym := exports[name]
+		if got := sym.kind + " " + formatJSArities(sym.arities); got != want {
+			t.Fatalf("export %s = %q, expected %q", name, got, want)
+		}
+	}
+}
+
+func TestDiffJSPackage_ClassifiesExportChanges(t *testing.T) {
+	manifest := `{
+	"name": "@acme/ui",
+	"exports": {
+		".": { "types": "./dist/index.d.ts", "import": "./dist/index.js" },
+		"./utils": "./dist/utils.js"%s
+	}
+}`
+	oldFiles := map[string]string{
+		"packages/ui/package.json": fmt.Sprintf(manifest, `,
+		"./legacy": "./dist/legacy.js"`),
+		"packages/ui/src/index.ts": `export { Button } from './button'
+export * from './hooks'
+export function render(node, target) {}
+export const theme = {}
+`,
+		"packages/ui/src/button.tsx": "export function Button(props) {}\n",
+		"packages/ui/src/hooks.ts":   "export function useTheme() {}\nexport function useToggle(initial) {}\n",
+		"packages/ui/src/utils.ts":   "export function clamp(value, min, max) {}\n",
+		"packages/ui/src/legacy.ts":  "export function old() {}\n",
+	}
+	newFiles := map[string]string{
+		"packages/ui/package.json": fmt.Sprintf(manifest, `,
+		"./icons": "./dist/icons.js"`),
+		"packages/ui/src/index.ts": `export { Button } from './button'
+export * from './hooks'
+export function render(node, target, options) {}
+export function hydrate(node) {}
+export const theme = {}
+`,
+		"packages/ui/src/button.tsx": "export function Button(props) {}\n",
+		"packages/ui/src/hooks.ts":   "export function useTheme() {}\n",
+		"packages/ui/src/utils.ts":   "export function clamp(value, min, max) {}\n",
+		"packages/ui/src/icons.ts":   "export const Icon = 1\n",
+	}
+
+	oldTree := newJSTree(memoryJSSource(oldFiles))
+	newTree := newJSTree(memoryJSSource(newFiles))
+	oldPkg, err := oldTree.loadPackage("packages/ui")
+	if err != nil {
+		t.Fatalf("failed to load old package: %v", err)
+	}
+	newPkg, err := newTree.loadPackage("packages/ui")
+	if err != nil {
+		t.Fatalf("failed to load new package: %v", err)
+	}
+
+	s := newReleaseSignals()
+	diffJSPackage("packages/ui", oldTree, newTree, oldPkg, newPkg, s)
+
+	expected := map[string]map[string]string{
+		"packages/ui/package.json": {
+			`removed package entry exports["./legacy"]`: "major",
+			`added package entry exports["./icons"]`:    "minor",
+		},
+		"packages/ui/src/index.ts": {
+			"changed arity of render from 2 to 3": "major",
+			"added export hydrate":                "minor",
+		},
+		"packages/ui/src/hooks.ts": {
+			"removed export useToggle": "major",
+		},
+	}
+	if len(s.fileRules) != len(expected) {
+		t.Fatalf("expected rules for %d files, got %+v", len(expected), s.fileRules)
+	}
+	for file, reasons := range expected {
+		rules := s.fileRules[file]
+		if len(rules) != len(reasons) {
+			t.Fatalf("expected %d rules for %s, got %+v", len(reasons), file, rules)
+		}
+		for _, rule := range rules {
+			if reasons[rule.reason] != rule.severity {
+				t.Fatalf("unexpected rule for %s: %+v", file, rule)
+			}
+		}
+	}
+	if !s.major || !s.minor {
+		t.Fatalf("expected major and minor signals")
+	}
+}
+
+func TestDiffJSPackage_SkipsPrivatePackages(t *testing.T) {
+	files := map[string]string{
+		"package.json":        `{"private": true, "main": "resources/js/app.js"}`,
+		"resources/js/app.js": "export function boot() {}\n",
+	}
+	tree := newJSTree(memoryJSSource(files))
+	pkg, err := tree.loadPackage(".")
+	if err != nil {
+		t.Fatalf("failed to load package: %v", err)
+	}
+
+	s := newReleaseSignals()
+	diffJSPackage(".", tree, newJSTree(memoryJSSource(map[string]string{})), pkg, nil, s)
+	if s.major || s.minor || len(s.fileRules) != 0 {
+		t.Fatalf("expected private package to be skipped, got %+v", s.fileRules)
+	}
+}
+
+func TestJSAnalyzer_Match(t *testing.T) {
+	a := jsAnalyzer{}
+	for file, expected := range map[string]bool{
+		"src/index.ts":                 true,
+		"packages/ui/src/button.tsx":   true,
+		"types/index.d.ts":             true,
+		"lib/server.mjs":               true,
+		"packages/ui/package.json":     true,
+		"src/index.test.ts":            false,
+		"node_modules/lodash/index.js": false,
+		"src/__tests__/button.tsx":     false,
+		"app/Models/User.php":          false,
+	} {
+		if got := a.Match(file); got != expected {
+			t.Fatalf("Match(%q) = %v, expected %v", file, got, expected)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package releasetype
+
+import (
+	"strings"
+)
+
+type jsTokenKind int
+
+const (
+	jsTokIdent jsTokenKind = iota
+	jsTokString
+	jsTokTemplate
+	jsTokNumber
+	jsTokRegexp
+	jsTokPunct
+)
+
+type jsToken struct {
+	kind  jsTokenKind
+	text  string
+	value string
+	line  int
+}
+
+// jsRegexpPrecedents are the tokens after which a slash starts a regular
+// expression literal rather than a division.
+var jsRegexpPrecedents = map[string]bool{
+	"(": true, ",": true, "=": true, ":": true, "[": true, "!": true, "&": true,
+	"|": true, "?": true, "{": true, "}": true, ";": true, "+": true, "-": true,
+	"*": true, "%": true, "<": true, ">": true, "~": true, "^": true, "=>": true,
+	"return": true, "typeof": true, "case": true, "do": true, "else": true,
+	"in": true, "of": true, "new": true, "delete": true, "void": true, "throw": true,
+}
+
+// tokenizeJS splits JavaScript or TypeScript source into tokens. Comments and
+// whitespace are dropped; strings keep their unescaped value. Punctuation is
+// emitted one character at a time except for `=>` and `...`, which is enough
+// to track nesting and find declarations.
+func tokenizeJS(src string) []jsToken {
+	l := &jsLexer{src: src, line: 1}
+	l.run()
+	return l.tokens
+}
+
+type jsLexer struct {
+	src    string
+	pos    int
+	line   int
+	tokens []jsToken
+}
+
+func (l *jsLexer) run() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			l.skipLineComment()
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			l.skipBlockComment()
+		case c == '\'' || c == '"':
+			l.lexString(c)
+		case c == '`':
+			l.lexTemplate()
+		case isJSIdentStart(c):
+			l.lexWhile(jsTokIdent, isJSIdentPart)
+		case c >= '0' && c <= '9':
+			l.lexWhile(jsTokNumber, isJSIdentPart)
+		case c == '/' && l.regexpAllowed():
+			l.lexRegexp()
+		case strings.HasPrefix(l.src[l.pos:], "=>"), strings.HasPrefix(l.src[l.pos:], "..."):
+			n := 2
+			if c == '.' {
+				n = 3
+			}
+			l.emit(jsTokPunct, l.src[l.pos:l.pos+n], "", l.line)
+			l.pos += n
+		default:
+			l.emit(jsTokPunct, string(c), "", l.line)
+			l.pos++
+		}
+	}
+}
+
+func (l *jsLexer) emit(kind jsTokenKind, text, value string, line int) {
+	l.tokens = append(l.tokens, jsToken{kind: kind, text: text, value: value, line: line})
+}
+
+func (l *jsLexer) lexWhile(kind jsTokenKind, accept func(byte) bool) {
+	start := l.pos
+	for l.pos < len(l.src) && accept(l.src[l.pos]) {
+		l.pos++
+	}
+	l.emit(kind, l.src[start:l.pos], "", l.line)
+}
+
+func (l *jsLexer) skipLineComment() {
+	idx := strings.IndexByte(l.src[l.pos:], '\n')
+	if idx < 0 {
+		l.pos = len(l.src)
+		return
+	}
+	l.pos += idx
+}
+
+func (l *jsLexer) skipBlockComment() {
+	idx := strings.Index(l.src[l.pos+2:], "*/")
+	end := len(l.src)
+	if idx >= 0 {
+		end = l.pos + 2 + idx + 2
+	}
+	l.line += strings.Count(l.src[l.pos:end], "\n")
+	l.pos = end
+}
+
+func (l *jsLexer) lexString(quote byte) {
+	start, line := l.pos, l.line
+	var value strings.Builder
+	l.pos++
+	for l.pos < len(l.src) && l.src[l.pos] != quote && l.src[l.pos] != '\n' {
+		if l.src[l.pos] == '\\' && l.pos+1 < len(l.src) {
+			l.pos++
+		}
+		value.WriteByte(l.src[l.pos])
+		l.pos++
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == quote {
+		l.pos++
+	}
+	l.emit(jsTokString, l.src[start:l.pos], value.String(), line)
+}
+
+// lexTemplate consumes a template literal including any nested `${...}`
+// substitutions.
+func (l *jsLexer) lexTemplate() {
+	start, line := l.pos, l.line
+	l.pos = l.skipTemplate(l.pos)
+	l.emit(jsTokTemplate, l.src[start:l.pos], "", line)
+}
+
+func (l *jsLexer) skipTemplate(pos int) int {
+	pos++
+	for pos < len(l.src) {
+		switch {
+		case l.src[pos] == '\\':
+			pos += 2
+			continue
+		case l.src[pos] == '`':
+			return pos + 1
+		case strings.HasPrefix(l.src[pos:], "${"):
+			pos = l.skipSubstitution(pos + 2)
+			continue
+		case l.src[pos] == '\n':
+			l.line++
+		}
+		pos++
+	}
+	return len(l.src)
+}
+
+func (l *jsLexer) skipSubstitution(pos int) int {
+	depth := 1
+	for pos < len(l.src) {
+		switch c := l.src[pos]; c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return pos + 1
+			}
+		case '`':
+			pos = l.skipTemplate(pos)
+			continue
+		case '\'', '"':
+			for pos++; pos < len(l.src) && l.src[pos] != c && l.src[pos] != '\n'; pos++ {
+				if l.src[pos] == '\\' {
+					pos++
+				}
+			}
+		case '\n':
+			l.line++
+		}
+		pos++
+	}
+	return len(l.src)
+}
+
+func (l *jsLexer) regexpAllowed() bool {
+	if len(l.tokens) == 0 {
+		return true
+	}
+	last := l.tokens[len(l.tokens)-1]
+	if last.kind != jsTokPunct && last.kind != jsTokIdent {
+		return false
+	}
+	return jsRegexpPrecedents[last.text]
+}
+
+func (l *jsLexer) lexRegexp() {
+	start := l.pos
+	inClass := false
+	for l.pos++; l.pos < len(l.src) && l.src[l.pos] != '\n'; l.pos++ {
+		c := l.src[l.pos]
+		if c == '\\' {
+			l.pos++
+			continue
+		}
+		if c == '[' {
+			inClass = true
+		} else if c == ']' {
+			inClass = false
+		} else if c == '/' && !inClass {
+			l.pos++
+			break
+		}
+	}
+	for l.pos < len(l.src) && isJSIdentPart(l.src[l.pos]) {
+		l.pos++
+	}
+	l.emit(jsTokRegexp, l.src[start:l.pos], "", l.line)
+}
+
+func isJSIdentStart(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isJSIdentPart(c byte) bool {
+	return isJSIdentStart(c) || c >= '0' && c <= '9'
+}
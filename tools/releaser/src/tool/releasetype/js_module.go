@@ -0,0 +1,647 @@
+package releasetype
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsSymbol is one name exported by a JavaScript or TypeScript module.
+// Functions keep the distinct parameter counts of their declarations
+// (several when TypeScript overloads are declared); arities is nil when the
+// export is not a function or its shape is unknown.
+type jsSymbol struct {
+	name    string
+	kind    string
+	arities []int
+	file    string
+	line    int
+}
+
+// jsReExport is an `export * from` or `export {a as b} from` clause whose
+// names live in another module.
+type jsReExport struct {
+	specifier string
+	names     map[string]string // exported name -> imported name; nil for `export *`
+	line      int
+}
+
+// jsModule holds the exports declared by one module, before re-exports from
+// other modules are resolved.
+type jsModule struct {
+	file      string
+	exports   map[string]jsSymbol
+	locals    map[string]jsSymbol
+	aliases   map[string]string // exported name -> local name
+	reExports []jsReExport
+}
+
+var jsDeclarationKeywords = map[string]string{
+	"function":  "function",
+	"class":     "class",
+	"interface": "interface",
+	"type":      "type",
+	"enum":      "enum",
+	"namespace": "namespace",
+	"module":    "namespace",
+	"const":     "const",
+	"let":       "variable",
+	"var":       "variable",
+}
+
+var jsDeclarationModifiers = map[string]bool{
+	"declare":  true,
+	"async":    true,
+	"abstract": true,
+}
+
+// parseJSModule collects the top-level declarations and export statements of
+// a module, including the CommonJS `module.exports` and `exports.name` forms.
+func parseJSModule(file, src string) *jsModule {
+	m := &jsModule{
+		file:    file,
+		exports: make(map[string]jsSymbol),
+		locals:  make(map[string]jsSymbol),
+		aliases: make(map[string]string),
+	}
+	toks := tokenizeJS(src)
+	depth := 0
+	for i := 0; i < len(toks); i++ {
+		tok := toks[i]
+		if tok.kind == jsTokPunct {
+			switch tok.text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+			}
+			continue
+		}
+		if depth != 0 || tok.kind != jsTokIdent || (i > 0 && toks[i-1].text == ".") {
+			continue
+		}
+
+		switch {
+		case tok.text == "export":
+			i = m.parseExport(toks, i+1)
+		case tok.text == "module" && jsTokensMatch(toks, i+1, ".", "exports"):
+			i = m.parseCommonJSExport(toks, i+3)
+		case tok.text == "exports" && jsTokensMatch(toks, i+1, ".") && i+2 < len(toks) && jsTokensMatch(toks, i+3, "="):
+			m.addExport(jsSymbol{name: toks[i+2].text, kind: "value", line: tok.line}, toks, i+4)
+			i += 3
+		default:
+			if sym, next, ok := parseJSDeclaration(toks, i); ok {
+				m.addLocal(sym)
+				i = next
+			}
+		}
+	}
+	return m
+}
+
+func (m *jsModule) parseExport(toks []jsToken, i int) int {
+	if i >= len(toks) {
+		return i
+	}
+	switch tok := toks[i]; {
+	case tok.text == "type" && jsTokensMatch(toks, i+1, "{"):
+		return m.parseExportList(toks, i+1)
+	case tok.text == "{":
+		return m.parseExportList(toks, i)
+	case tok.text == "*":
+		return m.parseExportStar(toks, i)
+	case tok.text == "=":
+		return m.parseDefaultExport(toks, i+1)
+	case tok.text == "default":
+		return m.parseDefaultExport(toks, i+1)
+	case tok.text == "import" && i+1 < len(toks):
+		m.exports[toks[i+1].text] = jsSymbol{name: toks[i+1].text, kind: "namespace", file: m.file, line: tok.line}
+		return i + 1
+	}
+
+	start := i
+	for start < len(toks) && jsDeclarationModifiers[toks[start].text] {
+		start++
+	}
+	if start < len(toks) && (toks[start].text == "const" || toks[start].text == "let" || toks[start].text == "var") && !jsTokensMatch(toks, start+1, "enum") {
+		return m.parseVariableExports(toks, start)
+	}
+	sym, next, ok := parseJSDeclaration(toks, i)
+	if !ok {
+		return i - 1
+	}
+	sym.file = m.file
+	m.addLocal(sym)
+	m.exports[sym.name] = m.locals[sym.name]
+	return next
+}
+
+// parseExportList handles `export { a, b as c }` with an optional `from`.
+func (m *jsModule) parseExportList(toks []jsToken, open int) int {
+	names := make(map[string]string)
+	lines := make(map[string]int)
+	i := open + 1
+	for ; i < len(toks) && toks[i].text != "}"; i++ {
+		if toks[i].text == "," || toks[i].text == "type" && i+1 < len(toks) && toks[i+1].kind == jsTokIdent {
+			continue
+		}
+		local := jsTokenName(toks[i])
+		exported := local
+		if jsTokensMatch(toks, i+1, "as") && i+2 < len(toks) {
+			exported = jsTokenName(toks[i+2])
+			i += 2
+		}
+		names[exported] = local
+		lines[exported] = toks[i].line
+	}
+	if jsTokensMatch(toks, i+1, "from") && i+2 < len(toks) {
+		m.reExports = append(m.reExports, jsReExport{specifier: toks[i+2].value, names: names, line: toks[open].line})
+		return i + 2
+	}
+	for exported, local := range names {
+		m.aliases[exported] = local
+		m.exports[exported] = jsSymbol{name: exported, kind: "value", file: m.file, line: lines[exported]}
+	}
+	return i
+}
+
+// parseExportStar handles `export * from 'x'` and `export * as ns from 'x'`.
+func (m *jsModule) parseExportStar(toks []jsToken, i int) int {
+	if jsTokensMatch(toks, i+1, "as") && i+2 < len(toks) {
+		name := jsTokenName(toks[i+2])
+		m.exports[name] = jsSymbol{name: name, kind: "namespace", file: m.file, line: toks[i].line}
+		return i + 4
+	}
+	if jsTokensMatch(toks, i+1, "from") && i+2 < len(toks) {
+		m.reExports = append(m.reExports, jsReExport{specifier: toks[i+2].value, line: toks[i].line})
+		return i + 2
+	}
+	return i
+}
+
+func (m *jsModule) parseDefaultExport(toks []jsToken, i int) int {
+	if i >= len(toks) {
+		return i
+	}
+	if sym, next, ok := parseJSDeclaration(toks, i); ok {
+		sym.file = m.file
+		if sym.name != "" {
+			m.addLocal(sym)
+		}
+		sym.name = "default"
+		m.exports["default"] = sym
+		return next
+	}
+	if toks[i].kind == jsTokIdent && !jsTokensMatch(toks, i+1, "(") && !jsTokensMatch(toks, i+1, "=>") && !jsTokensMatch(toks, i+1, ".") {
+		m.aliases["default"] = toks[i].text
+		m.exports["default"] = jsSymbol{name: "default", kind: "value", file: m.file, line: toks[i].line}
+		return i
+	}
+	m.addExport(jsSymbol{name: "default", kind: "value", line: toks[i].line}, toks, i)
+	return i - 1
+}
+
+// parseCommonJSExport handles `module.exports = ...` and
+// `module.exports.name = ...`; i points just past `exports`.
+func (m *jsModule) parseCommonJSExport(toks []jsToken, i int) int {
+	switch {
+	case jsTokensMatch(toks, i, ".") && i+1 < len(toks) && jsTokensMatch(toks, i+2, "="):
+		m.addExport(jsSymbol{name: toks[i+1].text, kind: "value", line: toks[i+1].line}, toks, i+3)
+		return i + 2
+	case jsTokensMatch(toks, i, "=", "{"):
+		return m.parseCommonJSObject(toks, i+1)
+	case jsTokensMatch(toks, i, "="):
+		return m.parseDefaultExport(toks, i+1)
+	}
+	return i
+}
+
+// parseCommonJSObject reads the keys of `module.exports = { a, b: c }`.
+func (m *jsModule) parseCommonJSObject(toks []jsToken, open int) int {
+	depth := 0
+	expectKey := true
+	for i := open; i < len(toks); i++ {
+		switch toks[i].text {
+		case "(", "[", "{":
+			depth++
+			continue
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+			continue
+		case ",":
+			if depth == 1 {
+				expectKey = true
+			}
+			continue
+		}
+		if depth != 1 || !expectKey || (toks[i].kind != jsTokIdent && toks[i].kind != jsTokString) {
+			continue
+		}
+		expectKey = false
+		name := jsTokenName(toks[i])
+		if jsTokensMatch(toks, i+1, ":") {
+			m.addExport(jsSymbol{name: name, kind: "value", line: toks[i].line}, toks, i+2)
+			continue
+		}
+		if jsTokensMatch(toks, i+1, "(") {
+			m.exports[name] = jsSymbol{name: name, kind: "function", arities: []int{jsParamCount(toks, i+1)}, file: m.file, line: toks[i].line}
+			continue
+		}
+		m.aliases[name] = name
+		m.exports[name] = jsSymbol{name: name, kind: "value", file: m.file, line: toks[i].line}
+	}
+	return len(toks)
+}
+
+// parseVariableExports handles `export const a = ..., b = ...` including
+// destructuring patterns.
+func (m *jsModule) parseVariableExports(toks []jsToken, i int) int {
+	kind := jsDeclarationKeywords[toks[i].text]
+	line := toks[i].line
+	i++
+	for i < len(toks) {
+		if toks[i].text == "{" || toks[i].text == "[" {
+			end := jsMatchingClose(toks, i)
+			for _, name := range jsPatternNames(toks[i+1 : end]) {
+				m.exports[name] = jsSymbol{name: name, kind: kind, file: m.file, line: line}
+			}
+			i = end + 1
+		} else if toks[i].kind == jsTokIdent {
+			sym := jsSymbol{name: toks[i].text, kind: kind, file: m.file, line: toks[i].line}
+			i++
+			if jsTokensMatch(toks, i, ":") {
+				i = jsSkipTypeAnnotation(toks, i+1)
+			}
+			if jsTokensMatch(toks, i, "=") {
+				if arity, ok := jsFunctionExpressionArity(toks, i+1); ok {
+					sym.kind = "function"
+					sym.arities = []int{arity}
+				}
+			}
+			m.addLocal(sym)
+			m.exports[sym.name] = m.locals[sym.name]
+		} else {
+			return i - 1
+		}
+
+		next := jsSkipExpression(toks, i, line)
+		if !jsTokensMatch(toks, next, ",") {
+			return next - 1
+		}
+		i = next + 1
+	}
+	return i
+}
+
+// addExport records an export whose value is the expression at toks[i],
+// treating function and arrow expressions as functions.
+func (m *jsModule) addExport(sym jsSymbol, toks []jsToken, i int) {
+	sym.file = m.file
+	if arity, ok := jsFunctionExpressionArity(toks, i); ok {
+		sym.kind = "function"
+		sym.arities = []int{arity}
+	} else if jsTokensMatch(toks, i, "class") {
+		sym.kind = "class"
+	} else if i < len(toks) && toks[i].kind == jsTokIdent && !jsTokensMatch(toks, i+1, "(") && !jsTokensMatch(toks, i+1, ".") {
+		m.aliases[sym.name] = toks[i].text
+	}
+	m.exports[sym.name] = sym
+}
+
+// addLocal records a declaration, merging the arities of overloads.
+func (m *jsModule) addLocal(sym jsSymbol) {
+	sym.file = m.file
+	if existing, ok := m.locals[sym.name]; ok && existing.kind == sym.kind && sym.kind == "function" {
+		existing.arities = mergeJSArities(existing.arities, sym.arities)
+		m.locals[sym.name] = existing
+		return
+	}
+	m.locals[sym.name] = sym
+}
+
+// resolvedExports returns the module's own exports with local aliases
+// resolved to the declarations they point at.
+func (m *jsModule) resolvedExports() map[string]jsSymbol {
+	out := make(map[string]jsSymbol, len(m.exports))
+	for name, sym := range m.exports {
+		if local, ok := m.aliases[name]; ok {
+			if decl, ok := m.locals[local]; ok {
+				decl.name = name
+				decl.line = sym.line
+				sym = decl
+			}
+		}
+		out[name] = sym
+	}
+	return out
+}
+
+// parseJSDeclaration reads a function, class, interface, type, enum or
+// namespace declaration starting at toks[i], after any modifiers.
+func parseJSDeclaration(toks []jsToken, i int) (jsSymbol, int, bool) {
+	for i < len(toks) && jsDeclarationModifiers[toks[i].text] && jsTokensMatch(toks, i+1, "") {
+		i++
+	}
+	if i >= len(toks) || toks[i].kind != jsTokIdent {
+		return jsSymbol{}, i, false
+	}
+	line := toks[i].line
+	keyword := toks[i].text
+	if keyword == "const" && jsTokensMatch(toks, i+1, "enum") {
+		keyword = "enum"
+		i++
+	}
+	kind, ok := jsDeclarationKeywords[keyword]
+	if !ok {
+		return jsSymbol{}, i, false
+	}
+
+	switch kind {
+	case "const", "variable":
+		if i+1 >= len(toks) || toks[i+1].kind != jsTokIdent {
+			return jsSymbol{}, i, false
+		}
+		sym := jsSymbol{name: toks[i+1].text, kind: kind, line: line}
+		next := i + 2
+		if jsTokensMatch(toks, next, ":") {
+			next = jsSkipTypeAnnotation(toks, next+1)
+		}
+		if jsTokensMatch(toks, next, "=") {
+			if arity, ok := jsFunctionExpressionArity(toks, next+1); ok {
+				sym.kind = "function"
+				sym.arities = []int{arity}
+			}
+		}
+		return sym, next - 1, true
+	case "function":
+		next := i + 1
+		if jsTokensMatch(toks, next, "*") {
+			next++
+		}
+		sym := jsSymbol{kind: kind, line: line}
+		if next < len(toks) && toks[next].kind == jsTokIdent {
+			sym.name = toks[next].text
+			next++
+		}
+		next = jsSkipTypeParameters(toks, next)
+		if !jsTokensMatch(toks, next, "(") {
+			return jsSymbol{}, i, false
+		}
+		sym.arities = []int{jsParamCount(toks, next)}
+		return sym, jsMatchingClose(toks, next), true
+	case "type":
+		if i+1 >= len(toks) || toks[i+1].kind != jsTokIdent || !(jsTokensMatch(toks, i+2, "=") || jsTokensMatch(toks, i+2, "<")) {
+			return jsSymbol{}, i, false
+		}
+	case "namespace":
+		if i+1 >= len(toks) || toks[i+1].kind != jsTokIdent {
+			return jsSymbol{}, i, false
+		}
+	}
+
+	sym := jsSymbol{kind: kind, line: line}
+	if i+1 < len(toks) && toks[i+1].kind == jsTokIdent && toks[i+1].text != "extends" && toks[i+1].text != "implements" {
+		sym.name = toks[i+1].text
+		return sym, i + 1, true
+	}
+	return sym, i, kind == "class"
+}
+
+// jsFunctionExpressionArity reports the parameter count of a function or
+// arrow function expression starting at toks[i].
+func jsFunctionExpressionArity(toks []jsToken, i int) (int, bool) {
+	if jsTokensMatch(toks, i, "async") {
+		i++
+	}
+	if jsTokensMatch(toks, i, "function") {
+		i++
+		if jsTokensMatch(toks, i, "*") {
+			i++
+		}
+		if i < len(toks) && toks[i].kind == jsTokIdent {
+			i++
+		}
+		i = jsSkipTypeParameters(toks, i)
+		if jsTokensMatch(toks, i, "(") {
+			return jsParamCount(toks, i), true
+		}
+		return 0, false
+	}
+	if i < len(toks) && toks[i].kind == jsTokIdent && jsTokensMatch(toks, i+1, "=>") {
+		return 1, true
+	}
+	i = jsSkipTypeParameters(toks, i)
+	if !jsTokensMatch(toks, i, "(") {
+		return 0, false
+	}
+	end := jsMatchingClose(toks, i)
+	if jsTokensMatch(toks, end+1, "=>") || jsTokensMatch(toks, end+1, ":") {
+		return jsParamCount(toks, i), true
+	}
+	return 0, false
+}
+
+// jsParamCount counts the parameters in the list opened at toks[open],
+// ignoring a TypeScript `this` parameter.
+func jsParamCount(toks []jsToken, open int) int {
+	count := 0
+	depth := 0
+	empty := true
+	for i := open; i < len(toks); i++ {
+		switch toks[i].text {
+		case "(", "[", "{", "<":
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ")", "]", "}", ">":
+			depth--
+			if depth == 0 {
+				if !empty {
+					count++
+				}
+				return count
+			}
+		case ",":
+			if depth == 1 {
+				if !empty {
+					count++
+				}
+				empty = true
+				continue
+			}
+		}
+		if depth == 1 && empty && toks[i].text == "this" && jsTokensMatch(toks, i+1, ":") {
+			count--
+		}
+		empty = false
+	}
+	return count
+}
+
+// jsSkipTypeParameters skips a `<...>` type parameter list at toks[i].
+func jsSkipTypeParameters(toks []jsToken, i int) int {
+	if !jsTokensMatch(toks, i, "<") {
+		return i
+	}
+	depth := 0
+	for ; i < len(toks); i++ {
+		switch toks[i].text {
+		case "<":
+			depth++
+		case ">":
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// jsSkipTypeAnnotation skips a type annotation and returns the index of the
+// `=`, `,` or `;` that ends it.
+func jsSkipTypeAnnotation(toks []jsToken, i int) int {
+	depth := 0
+	for ; i < len(toks); i++ {
+		switch toks[i].text {
+		case "(", "[", "{", "<":
+			depth++
+		case ")", "]", "}", ">":
+			depth--
+		case "=", ",", ";":
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// jsSkipExpression returns the index of the `,` or `;` ending the expression
+// at toks[i], or of the first token of the next statement when semicolons
+// are omitted.
+func jsSkipExpression(toks []jsToken, i, line int) int {
+	depth := 0
+	last := line
+	for ; i < len(toks); i++ {
+		tok := toks[i]
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth < 0 {
+				return i
+			}
+		case ",", ";":
+			if depth == 0 {
+				return i
+			}
+		}
+		if depth == 0 && tok.line > last && tok.kind == jsTokIdent && jsStatementStart(tok.text) {
+			return i
+		}
+		last = tok.line
+	}
+	return i
+}
+
+func jsStatementStart(word string) bool {
+	switch word {
+	case "export", "import", "const", "let", "var", "function", "class", "interface", "type", "enum", "module", "exports":
+		return true
+	}
+	return false
+}
+
+// jsPatternNames lists the bindings introduced by a destructuring pattern.
+func jsPatternNames(toks []jsToken) []string {
+	var names []string
+	depth := 0
+	for i, tok := range toks {
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+			continue
+		case ")", "]", "}":
+			depth--
+			continue
+		}
+		if tok.kind != jsTokIdent || (i > 0 && toks[i-1].text == "=") {
+			continue
+		}
+		if jsTokensMatch(toks, i+1, ":") || jsTokensMatch(toks, i+1, "{") || jsTokensMatch(toks, i+1, "[") {
+			continue
+		}
+		names = append(names, tok.text)
+	}
+	return names
+}
+
+func jsMatchingClose(toks []jsToken, open int) int {
+	depth := 0
+	for i := open; i < len(toks); i++ {
+		switch toks[i].text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(toks) - 1
+}
+
+// jsTokensMatch reports whether the tokens starting at toks[i] have the given
+// texts. An empty text matches any identifier.
+func jsTokensMatch(toks []jsToken, i int, texts ...string) bool {
+	for k, text := range texts {
+		if i+k >= len(toks) {
+			return false
+		}
+		if text == "" {
+			if toks[i+k].kind != jsTokIdent {
+				return false
+			}
+			continue
+		}
+		if toks[i+k].text != text {
+			return false
+		}
+	}
+	return true
+}
+
+func jsTokenName(tok jsToken) string {
+	if tok.kind == jsTokString {
+		return tok.value
+	}
+	return tok.text
+}
+
+func mergeJSArities(a, b []int) []int {
+	seen := make(map[int]bool)
+	var out []int
+	for _, n := range append(append([]int{}, a...), b...) {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+func formatJSArities(arities []int) string {
+	parts := make([]string, 0, len(arities))
+	for _, n := range arities {
+		parts = append(parts, strconv.Itoa(n))
+	}
+	return strings.Join(parts, " or ")
+}
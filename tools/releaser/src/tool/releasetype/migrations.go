@@ -0,0 +1,228 @@
+package releasetype
+
+import (
+	"strings"
+
+	"releaser/tool/output"
+)
+
+type schemaOperation struct {
+	severity string
+	reason   string
+	line     int
+}
+
+var destructiveBlueprintMethods = map[string]string{
+	"dropcolumn":                  "drops column",
+	"dropcolumns":                 "drops columns",
+	"renamecolumn":                "renames column",
+	"dropforeign":                 "drops foreign key",
+	"dropindex":                   "drops index",
+	"dropunique":                  "drops unique index",
+	"dropprimary":                 "drops primary key",
+	"dropfulltext":                "drops fulltext index",
+	"dropspatialindex":            "drops spatial index",
+	"renameindex":                 "renames index",
+	"droptimestamps":              "drops timestamps",
+	"droptimestampstz":            "drops timestamps",
+	"dropsoftdeletes":             "drops soft deletes",
+	"dropsoftdeletestz":           "drops soft deletes",
+	"dropremembertoken":           "drops remember token",
+	"dropmorphs":                  "drops morph columns",
+	"dropconstrainedforeignid":    "drops foreign id",
+	"dropforeignidfor":            "drops foreign id",
+	"dropconstrainedforeignidfor": "drops foreign id",
+	"rename":                      "renames table",
+}
+
+var indexBlueprintMethods = map[string]bool{
+	"index":        true,
+	"unique":       true,
+	"primary":      true,
+	"foreign":      true,
+	"fulltext":     true,
+	"spatialindex": true,
+}
+
+// incrementingBlueprintMethods add auto-incrementing columns, which the
+// database fills in for existing and new rows.
+var incrementingBlueprintMethods = map[string]bool{
+	"id":                true,
+	"increments":        true,
+	"tinyincrements":    true,
+	"smallincrements":   true,
+	"mediumincrements":  true,
+	"integerincrements": true,
+	"bigincrements":     true,
+}
+
+func init() {
+	register(migrationAnalyzer{})
+}
+
+// migrationAnalyzer classifies Laravel migrations by the schema operations
+// their up() method performs.
+type migrationAnalyzer struct{}
+
+func (migrationAnalyzer) Name() string {
+	return "migrations"
+}
+
+func (migrationAnalyzer) Match(file string) bool {
+	return isMigrationFile(file)
+}
+
+func (migrationAnalyzer) Analyze(r diffRange, files []string) *releaseSignals {
+	signals := newReleaseSignals()
+	for _, file := range files {
+		output.VeryVerbose("Analyzing migration file: " + file)
+		oldSrc, newSrc, err := r.readFiles(file)
+		if err != nil {
+			output.Warn("Failed to read " + file + ": " + err.Error())
+			continue
+		}
+		classifyMigration(file, oldSrc, newSrc, signals)
+	}
+	return signals
+}
+
+func isMigrationFile(file string) bool {
+	return strings.HasPrefix(file, "database/migrations/") && strings.HasSuffix(file, ".php")
+}
+
+func classifyMigration(file, oldSrc, newSrc string, signals *releaseSignals) {
+	if strings.TrimSpace(newSrc) == "" {
+		markMajorForFile(signals, file, "removed migration", "")
+		return
+	}
+
+	known := make(map[string]bool)
+	for _, op := range parseMigrationOperations(oldSrc) {
+		known[op.severity+"|"+op.reason] = true
+	}
+
+	lines := strings.Split(newSrc, "\n")
+	found := false
+	for _, op := range parseMigrationOperations(newSrc) {
+		if known[op.severity+"|"+op.reason] {
+			continue
+		}
+		found = true
+		snippet := snippetBlock(lines, op.line-1, 1, "+ ")
+		if op.severity == "major" {
+			markMajorForFile(signals, file, op.reason, snippet)
+		} else {
+			markMinorForFile(signals, file, op.reason, snippet)
+		}
+	}
+	if !found {
+		markPatchForFile(signals, file, "no schema changes detected", "")
+	}
+}
+
+// parseMigrationOperations lists the Schema builder operations performed by
+// the up() method of a migration. The down() method is ignored.
+func parseMigrationOperations(src string) []schemaOperation {
+	toks := phpMethodBody(tokenizePHP(src), "up")
+	var ops []schemaOperation
+	for i := 0; i < len(toks); i++ {
+		if !strings.EqualFold(toks[i].text, "Schema") && !strings.HasSuffix(toks[i].text, "\\Schema") {
+			continue
+		}
+		if i+1 >= len(toks) || toks[i+1].text != "::" {
+			continue
+		}
+
+		calls, end := parsePHPCallChain(toks, i+2)
+		for _, call := range calls {
+			switch strings.ToLower(call.name) {
+			case "create":
+				ops = append(ops, schemaOperation{"minor", "creates table " + call.arg(0), call.line})
+			case "drop", "dropifexists":
+				ops = append(ops, schemaOperation{"major", "drops table " + call.arg(0), call.line})
+			case "rename":
+				ops = append(ops, schemaOperation{"major", "renames table " + call.arg(0) + " to " + call.arg(1), call.line})
+			case "dropcolumns":
+				ops = append(ops, schemaOperation{"major", "drops columns on table " + call.arg(0), call.line})
+			case "dropalltables", "dropallviews", "dropalltypes":
+				ops = append(ops, schemaOperation{"major", strings.ToLower(call.name), call.line})
+			case "table":
+				ops = append(ops, parseBlueprintOperations(toks, i+2, end, call.arg(0))...)
+			}
+		}
+		i = end
+	}
+	return ops
+}
+
+// parseBlueprintOperations classifies the `$table->...` statements found in
+// the closure passed to Schema::table().
+func parseBlueprintOperations(toks []phpToken, start, end int, table string) []schemaOperation {
+	var ops []schemaOperation
+	for i := start; i < end; i++ {
+		if toks[i].kind != phpTokVariable || i+1 >= end || toks[i+1].text != "->" {
+			continue
+		}
+		calls, stmtEnd := parsePHPCallChain(toks, i+2)
+		if len(calls) == 0 {
+			continue
+		}
+		ops = append(ops, classifyBlueprintStatement(calls, table))
+		i = stmtEnd
+	}
+	return ops
+}
+
+func classifyBlueprintStatement(calls []phpCall, table string) schemaOperation {
+	first := calls[0]
+	method := strings.ToLower(first.name)
+	subject := strings.TrimSpace(first.arg(0) + " on table " + table)
+
+	for _, call := range calls[1:] {
+		if strings.EqualFold(call.name, "change") {
+			return schemaOperation{"major", "changes column " + subject, first.line}
+		}
+	}
+	if reason, ok := destructiveBlueprintMethods[method]; ok {
+		if method == "renamecolumn" || method == "renameindex" {
+			subject = first.arg(0) + " to " + first.arg(1) + " on table " + table
+		}
+		if first.arg(0) == "" {
+			subject = "on table " + table
+		}
+		return schemaOperation{"major", reason + " " + subject, first.line}
+	}
+	if indexBlueprintMethods[method] {
+		return schemaOperation{"minor", "adds " + method + " " + subject, first.line}
+	}
+
+	if first.arg(0) == "" {
+		return schemaOperation{"minor", "adds " + first.name + " columns on table " + table, first.line}
+	}
+	if incrementingBlueprintMethods[method] {
+		return schemaOperation{"minor", "adds incrementing column " + subject, first.line}
+	}
+
+	// A NOT NULL column without a default fails on populated tables and
+	// breaks inserts that do not set it.
+	column := ""
+	for _, call := range calls[1:] {
+		switch strings.ToLower(call.name) {
+		case "nullable":
+			if !strings.EqualFold(call.arg(0), "false") {
+				column = "nullable column"
+			}
+		case "default", "usecurrent":
+			column = "column with default"
+		case "storedas", "virtualas":
+			column = "generated column"
+		}
+		if column != "" {
+			break
+		}
+	}
+	if column == "" {
+		return schemaOperation{"major", "adds required column " + subject + " without a default", first.line}
+	}
+	return schemaOperation{"minor", "adds " + column + " " + subject, first.line}
+}
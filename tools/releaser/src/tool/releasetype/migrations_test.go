@@ -0,0 +1,106 @@
+package releasetype
+
+import "testing"
+
+const createUsersMigration = `<?php
+
+use Illuminate\Database\Migrations\Migration;
+use Illuminate\Database\Schema\Blueprint;
+use Illuminate\Support\Facades\Schema;
+
+return new class extends Migration
+{
+    public function up(): void
+    {
+        Schema::create('users', function (Blueprint $table) {
+            $table->id();
+            $table->string('email')->unique();
+        });
+    }
+
+    public function down(): void
+    {
+        Schema::dropIfExists('users');
+    }
+};
+`
+
+func TestParseMigrationOperations_IgnoresDown(t *testing.T) {
+	ops := parseMigrationOperations(createUsersMigration)
+	if len(ops) != 1 {
+		t.Fatalf("expected 1 operation, got %+v", ops)
+	}
+	if ops[0].severity != "minor" || ops[0].reason != "creates table users" {
+		t.Fatalf("unexpected operation %+v", ops[0])
+	}
+}
+
+func TestParseMigrationOperations_ClassifiesBlueprintCalls(t *testing.T) {
+	src := `<?php
+return new class extends Migration {
+    public function up(): void
+    {
+        Schema::table('users', function (Blueprint $table) {
+            $table->string('nickname')->nullable();
+            $table->dropColumn(['legacy_id', 'old_flag']);
+            $table->renameColumn('name', 'full_name');
+            $table->integer('age')->unsigned()->change();
+            $table->dropForeign('users_team_id_foreign');
+            $table->index('email');
+            $table->boolean('active')->default(true);
+            $table->foreignId('team_id')->constrained();
+            $table->string('locale')->nullable(false);
+        });
+        Schema::rename('posts', 'articles');
+    }
+};
+`
+	expected := []schemaOperation{
+		{"minor", "adds nullable column nickname on table users", 6},
+		{"major", "drops column legacy_id, old_flag on table users", 7},
+		{"major", "renames column name to full_name on table users", 8},
+		{"major", "changes column age on table users", 9},
+		{"major", "drops foreign key users_team_id_foreign on table users", 10},
+		{"minor", "adds index email on table users", 11},
+		{"minor", "adds column with default active on table users", 12},
+		{"major", "adds required column team_id on table users without a default", 13},
+		{"major", "adds required column locale on table users without a default", 14},
+		{"major", "renames table posts to articles", 16},
+	}
+
+	ops := parseMigrationOperations(src)
+	if len(ops) != len(expected) {
+		t.Fatalf("expected %d operations, got %+v", len(expected), ops)
+	}
+	for i := range expected {
+		if ops[i] != expected[i] {
+			t.Fatalf("operation %d: expected %+v, got %+v", i, expected[i], ops[i])
+		}
+	}
+}
+
+func TestClassifyMigration_NewAdditiveMigrationIsMinor(t *testing.T) {
+	file := "database/migrations/2026_01_01_000000_create_users_table.php"
+	s := newReleaseSignals()
+
+	classifyMigration(file, "", createUsersMigration, s)
+
+	if s.major || !s.minor {
+		t.Fatalf("expected minor only, got major=%v minor=%v", s.major, s.minor)
+	}
+	rules := s.fileRules[file]
+	if len(rules) != 1 || rules[0].snippet != "+         Schema::create('users', function (Blueprint $table) {" {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+}
+
+func TestClassifyMigration_RemovedMigrationIsMajor(t *testing.T) {
+	file := "database/migrations/2026_01_01_000000_create_users_table.php"
+	s := newReleaseSignals()
+
+	classifyMigration(file, createUsersMigration, "", s)
+
+	if !s.major {
+		t.Fatalf("expected major signal for removed migration")
+	}
+}
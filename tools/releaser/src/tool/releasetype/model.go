@@ -6,15 +6,13 @@ import (
 	"strings"
 
 	"releaser/tool/output"
+	"releaser/tool/shared"
 )
 
 type changeBuckets struct {
-	phpFiles      []string
-	migrations    []string
-	docs          []string
-	configs       []string
-	views         []string
-	composerFiles []string
+	analyzers []analyzer
+	analyzed  map[string][]string
+	docs      []string
 }
 
 type releaseSignals struct {
@@ -56,6 +54,23 @@ func (s *releaseSignals) addFileRule(file, severity, reason, snippet string) {
 	})
 }
 
+// rules flattens the file rules, sorted by file, for release notes.
+func (s *releaseSignals) rules() []shared.Rule {
+	files := make([]string, 0, len(s.fileRules))
+	for file := range s.fileRules {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var rules []shared.Rule
+	for _, file := range files {
+		for _, rule := range s.fileRules[file] {
+			rules = append(rules, shared.Rule{File: file, Severity: rule.severity, Reason: rule.reason, Snippet: rule.snippet})
+		}
+	}
+	return rules
+}
+
 func (s *releaseSignals) emitRules() {
 	for _, rule := range s.globalRules {
 		output.Info("- " + rule)
@@ -108,7 +123,7 @@ func renderBoxedFileRules(file string, rules []fileRule) {
 }
 
 func (b changeBuckets) hasOnlyDocs() bool {
-	return len(b.phpFiles) == 0 && len(b.docs) > 0
+	return len(b.analyzed) == 0 && len(b.docs) > 0
 }
 
 func (b changeBuckets) docsFiles() []string {
@@ -116,15 +131,11 @@ func (b changeBuckets) docsFiles() []string {
 }
 
 func (b changeBuckets) summary() string {
-	return fmt.Sprintf(
-		"php=%d migrations=%d docs=%d configs=%d views=%d composer=%d",
-		len(b.phpFiles),
-		len(b.migrations),
-		len(b.docs),
-		len(b.configs),
-		len(b.views),
-		len(b.composerFiles),
-	)
+	var parts []string
+	for _, analyzer := range b.analyzers {
+		parts = append(parts, fmt.Sprintf("%s=%d", analyzer.Name(), len(b.analyzed[analyzer.Name()])))
+	}
+	return strings.Join(append(parts, fmt.Sprintf("docs=%d", len(b.docs))), " ")
 }
 
 func trimNonEmptyLines(raw string) []string {
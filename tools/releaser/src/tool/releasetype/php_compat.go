@@ -0,0 +1,231 @@
+package releasetype
+
+import (
+	"sort"
+	"strings"
+)
+
+type phpCompatOptions struct {
+	namedArguments bool
+}
+
+type phpVerdict struct {
+	severity string
+	reason   string
+}
+
+type phpTypeRelation int
+
+const (
+	phpTypeSame phpTypeRelation = iota
+	phpTypeWider
+	phpTypeNarrower
+	phpTypeIncompatible
+)
+
+// comparePHPSignatures classifies every difference between two declarations
+// of the same function from the point of view of its callers.
+func comparePHPSignatures(oldFn, newFn *phpFunction, opts phpCompatOptions) []phpVerdict {
+	var verdicts []phpVerdict
+	add := func(severity, reason string) {
+		verdicts = append(verdicts, phpVerdict{severity: severity, reason: reason + " for " + newFn.name})
+	}
+
+	for i := 0; i < len(oldFn.params) || i < len(newFn.params); i++ {
+		switch {
+		case i >= len(newFn.params):
+			add("major", "removed parameter "+oldFn.params[i].name)
+		case i >= len(oldFn.params):
+			param := newFn.params[i]
+			if param.hasDefault || param.variadic {
+				add("minor", "added optional parameter "+param.name)
+			} else {
+				add("major", "added required parameter "+param.name)
+			}
+		default:
+			for _, v := range comparePHPParams(oldFn.params[i], newFn.params[i], opts) {
+				add(v.severity, v.reason)
+			}
+		}
+	}
+
+	if oldFn.byRef != newFn.byRef {
+		add("major", "changed by-reference return")
+	}
+	switch comparePHPTypes(oldFn.returnType, newFn.returnType) {
+	case phpTypeNarrower:
+		add("minor", "narrowed return type to "+displayPHPType(newFn.returnType))
+	case phpTypeWider:
+		add("major", "widened return type to "+displayPHPType(newFn.returnType))
+	case phpTypeIncompatible:
+		add("major", "changed return type to "+displayPHPType(newFn.returnType))
+	}
+	return verdicts
+}
+
+func comparePHPParams(oldParam, newParam phpParam, opts phpCompatOptions) []phpVerdict {
+	var verdicts []phpVerdict
+	add := func(severity, reason string) {
+		verdicts = append(verdicts, phpVerdict{severity: severity, reason: reason})
+	}
+
+	if oldParam.name != newParam.name {
+		if opts.namedArguments {
+			add("major", "renamed parameter "+oldParam.name+" to "+newParam.name)
+		} else {
+			add("patch", "renamed parameter "+oldParam.name+" to "+newParam.name)
+		}
+	}
+	if oldParam.byRef != newParam.byRef {
+		add("major", "changed by-reference passing of "+newParam.name)
+	}
+	if oldParam.variadic != newParam.variadic {
+		add("major", "changed variadic parameter "+newParam.name)
+	}
+
+	switch comparePHPTypes(oldParam.typ, newParam.typ) {
+	case phpTypeWider:
+		add("minor", "widened type of "+newParam.name+" to "+displayPHPType(newParam.typ))
+	case phpTypeNarrower:
+		add("major", "narrowed type of "+newParam.name+" to "+displayPHPType(newParam.typ))
+	case phpTypeIncompatible:
+		add("major", "changed type of "+newParam.name+" to "+displayPHPType(newParam.typ))
+	}
+
+	switch {
+	case oldParam.hasDefault && !newParam.hasDefault && !newParam.variadic:
+		add("major", "made parameter "+newParam.name+" required")
+	case !oldParam.hasDefault && newParam.hasDefault && !oldParam.variadic:
+		add("minor", "made parameter "+newParam.name+" optional")
+	case oldParam.hasDefault && newParam.hasDefault && oldParam.def != newParam.def:
+		add("patch", "changed default of "+newParam.name+" to "+newParam.def)
+	}
+	return verdicts
+}
+
+// comparePHPTypes reports how the set of values accepted by newType relates to
+// the one accepted by oldType. A missing declaration is treated as mixed.
+func comparePHPTypes(oldType, newType string) phpTypeRelation {
+	oldSet := parsePHPTypeSet(oldType)
+	newSet := parsePHPTypeSet(newType)
+	oldInNew := phpTypeSetWithin(oldSet, newSet)
+	newInOld := phpTypeSetWithin(newSet, oldSet)
+	switch {
+	case oldInNew && newInOld:
+		return phpTypeSame
+	case oldInNew:
+		return phpTypeWider
+	case newInOld:
+		return phpTypeNarrower
+	default:
+		return phpTypeIncompatible
+	}
+}
+
+// parsePHPTypeSet splits a resolved type declaration into its union members.
+// Class names are already resolved against the namespace and imports by the
+// parser, so only the leading backslash and case are normalized here.
+// Intersection members are split and sorted, so `A&B` and `B&A` compare equal.
+func parsePHPTypeSet(typ string) []string {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	if typ == "" {
+		return []string{"mixed"}
+	}
+	var out []string
+	if strings.HasPrefix(typ, "?") {
+		out = append(out, "null")
+		typ = typ[1:]
+	}
+	for _, member := range strings.Split(typ, "|") {
+		var parts []string
+		for _, part := range strings.Split(strings.Trim(member, "()"), "&") {
+			if part = strings.TrimPrefix(part, "\\"); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) > 0 {
+			sort.Strings(parts)
+			out = append(out, strings.Join(parts, "&"))
+		}
+	}
+	return out
+}
+
+func phpTypeSetWithin(set, container []string) bool {
+	for _, member := range set {
+		if !phpTypeAccepts(container, member) {
+			return false
+		}
+	}
+	return true
+}
+
+// phpTypeAccepts reports whether a value of type member always satisfies one of
+// the container types. An intersection container is satisfied when each of its
+// parts accepts one of the parts of member.
+func phpTypeAccepts(container []string, member string) bool {
+	if member == "never" {
+		return true
+	}
+	memberParts := strings.Split(member, "&")
+	for _, c := range container {
+		satisfied := true
+		for _, part := range strings.Split(c, "&") {
+			if !phpPartAcceptsAny(part, memberParts) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true
+		}
+	}
+	return false
+}
+
+// phpPartAcceptsAny reports whether a value satisfying all of parts is always
+// accepted by the single type c. Only relations known without class
+// hierarchies are used.
+func phpPartAcceptsAny(c string, parts []string) bool {
+	for _, part := range parts {
+		if part == c {
+			return true
+		}
+		switch c {
+		case "mixed":
+			return true
+		case "float":
+			if part == "int" {
+				return true
+			}
+		case "bool":
+			if part == "true" || part == "false" {
+				return true
+			}
+		case "iterable":
+			if part == "array" || part == "traversable" {
+				return true
+			}
+		case "callable":
+			if part == "closure" {
+				return true
+			}
+		case "object":
+			if isPHPClassType(part) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isPHPClassType(name string) bool {
+	return !isPHPBuiltinType(name) || name == "self" || name == "static" || name == "parent"
+}
+
+func displayPHPType(typ string) string {
+	if typ == "" {
+		return "mixed"
+	}
+	return typ
+}
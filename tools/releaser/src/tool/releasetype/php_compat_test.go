@@ -0,0 +1,195 @@
+package releasetype
+
+import "testing"
+
+func TestComparePHPSignatures(t *testing.T) {
+	cases := []struct {
+		name     string
+		old      string
+		new      string
+		named    bool
+		expected []phpVerdict
+	}{
+		{
+			name:     "trailing optional parameter",
+			old:      "function run($a) {}",
+			new:      "function run($a, $b = null) {}",
+			expected: []phpVerdict{{"minor", "added optional parameter $b for run"}},
+		},
+		{
+			name:     "trailing required parameter",
+			old:      "function run($a) {}",
+			new:      "function run($a, $b) {}",
+			expected: []phpVerdict{{"major", "added required parameter $b for run"}},
+		},
+		{
+			name:     "removed parameter",
+			old:      "function run($a, $b) {}",
+			new:      "function run($a) {}",
+			expected: []phpVerdict{{"major", "removed parameter $b for run"}},
+		},
+		{
+			name:     "rename without named arguments",
+			old:      "function run($a) {}",
+			new:      "function run($b) {}",
+			expected: []phpVerdict{{"patch", "renamed parameter $a to $b for run"}},
+		},
+		{
+			name:     "rename with named arguments",
+			old:      "function run($a) {}",
+			new:      "function run($b) {}",
+			named:    true,
+			expected: []phpVerdict{{"major", "renamed parameter $a to $b for run"}},
+		},
+		{
+			name:     "widened parameter type",
+			old:      "function run(int $a) {}",
+			new:      "function run(int|string $a) {}",
+			expected: []phpVerdict{{"minor", "widened type of $a to int|string for run"}},
+		},
+		{
+			name:     "narrowed parameter type",
+			old:      "function run(?int $a) {}",
+			new:      "function run(int $a) {}",
+			expected: []phpVerdict{{"major", "narrowed type of $a to int for run"}},
+		},
+		{
+			name:     "covariant return type",
+			old:      "function run(): ?Model {}",
+			new:      "function run(): Model {}",
+			expected: []phpVerdict{{"minor", "narrowed return type to Model for run"}},
+		},
+		{
+			name:     "contravariant return type",
+			old:      "function run(): int {}",
+			new:      "function run(): int|string {}",
+			expected: []phpVerdict{{"major", "widened return type to int|string for run"}},
+		},
+		{
+			name:     "added return type",
+			old:      "function run() {}",
+			new:      "function run(): void {}",
+			expected: []phpVerdict{{"minor", "narrowed return type to void for run"}},
+		},
+		{
+			name:     "optional parameter made required",
+			old:      "function run($a = 1) {}",
+			new:      "function run($a) {}",
+			expected: []phpVerdict{{"major", "made parameter $a required for run"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldFn := parsePHPSource("<?php " + tc.old).functions["run"]
+			newFn := parsePHPSource("<?php " + tc.new).functions["run"]
+			got := comparePHPSignatures(oldFn, newFn, phpCompatOptions{namedArguments: tc.named})
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Fatalf("expected %v, got %v", tc.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestComparePHPTypes(t *testing.T) {
+	cases := []struct {
+		old      string
+		new      string
+		expected phpTypeRelation
+	}{
+		{"int", "int", phpTypeSame},
+		{"int|string", "string|int", phpTypeSame},
+		{"int", "float", phpTypeWider},
+		{"", "string", phpTypeNarrower},
+		{"string", "", phpTypeWider},
+		{"array", "iterable", phpTypeWider},
+		{"App\\Models\\User", "object", phpTypeWider},
+		{"int", "string", phpTypeIncompatible},
+		{"\\App\\Foo", "App\\Foo", phpTypeSame},
+		{"A&B", "B&A", phpTypeSame},
+		{"(A&B)|null", "null|(B&A)", phpTypeSame},
+		{"A&B", "A", phpTypeWider},
+		{"A", "A&B", phpTypeNarrower},
+		{"A&B&C", "A&B", phpTypeWider},
+		{"A&B", "object", phpTypeWider},
+		{"A&B", "A&C", phpTypeIncompatible},
+	}
+
+	for _, tc := range cases {
+		if got := comparePHPTypes(tc.old, tc.new); got != tc.expected {
+			t.Fatalf("comparePHPTypes(%q, %q) = %v, expected %v", tc.old, tc.new, got, tc.expected)
+		}
+	}
+}
+
+func TestComparePHPSignatures_ResolvesClassNames(t *testing.T) {
+	cases := []struct {
+		name     string
+		old      string
+		new      string
+		expected []phpVerdict
+	}{
+		{
+			name:     "relative and fully qualified name",
+			old:      "<?php namespace App; function run(Foo $a): Foo {}",
+			new:      "<?php namespace App; function run(\\App\\Foo $a): \\App\\Foo {}",
+			expected: nil,
+		},
+		{
+			name:     "import alias swapped",
+			old:      "<?php namespace App; use App\\Models\\User as Account; function run(Account $a) {}",
+			new:      "<?php namespace App; use App\\Models\\User; function run(User $a) {}",
+			expected: nil,
+		},
+		{
+			name:     "function import does not shadow a class",
+			old:      "<?php namespace App; function run(Foo $a) {}",
+			new:      "<?php namespace App; use function Lib\\Foo; function run(Foo $a) {}",
+			expected: nil,
+		},
+		{
+			name:     "import pointing at another class",
+			old:      "<?php namespace App; function run(User $a) {}",
+			new:      "<?php namespace App; use Lib\\User; function run(User $a) {}",
+			expected: []phpVerdict{{"major", "changed type of $a to Lib\\User for App\\run"}},
+		},
+		{
+			name:     "intersection members reordered",
+			old:      "<?php namespace App; use Countable; function run(Countable&Stringable $a) {}",
+			new:      "<?php namespace App; use Countable; function run(Stringable&Countable $a) {}",
+			expected: nil,
+		},
+		{
+			name:     "intersection member dropped",
+			old:      "<?php function run(Countable&Stringable $a) {}",
+			new:      "<?php function run(Countable $a) {}",
+			expected: []phpVerdict{{"minor", "widened type of $a to Countable for run"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var oldFn, newFn *phpFunction
+			for _, fn := range parsePHPSource(tc.old).functions {
+				oldFn = fn
+			}
+			for _, fn := range parsePHPSource(tc.new).functions {
+				newFn = fn
+			}
+			got := comparePHPSignatures(oldFn, newFn, phpCompatOptions{})
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Fatalf("expected %v, got %v", tc.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package releasetype
+
+import (
+	"strings"
+)
+
+type phpTokenKind int
+
+const (
+	phpTokInlineHTML phpTokenKind = iota
+	phpTokIdent
+	phpTokVariable
+	phpTokString
+	phpTokNumber
+	phpTokPunct
+)
+
+type phpToken struct {
+	kind  phpTokenKind
+	text  string
+	value string
+	line  int
+	start int
+	end   int
+}
+
+var phpPunctuators = []string{
+	"<<=", ">>=", "**=", "...", "<=>", "===", "!==", "??=", "?->",
+	"::", "=>", "->", "++", "--", "==", "!=", "<>", "<=", ">=", "&&", "||", "??",
+	"+=", "-=", "*=", "/=", ".=", "%=", "&=", "|=", "^=", "<<", ">>", "**", "#[",
+}
+
+// tokenizePHP splits PHP source into tokens. Comments and whitespace are
+// dropped; string literals keep their raw text and, for simple quoted strings,
+// their unescaped value.
+func tokenizePHP(src string) []phpToken {
+	l := &phpLexer{src: src, line: 1}
+	l.run()
+	return l.tokens
+}
+
+type phpLexer struct {
+	src    string
+	pos    int
+	line   int
+	inPHP  bool
+	tokens []phpToken
+}
+
+func (l *phpLexer) run() {
+	for l.pos < len(l.src) {
+		if !l.inPHP {
+			l.lexInlineHTML()
+			continue
+		}
+		l.lexPHP()
+	}
+}
+
+func (l *phpLexer) lexInlineHTML() {
+	start := l.pos
+	idx := strings.Index(l.src[l.pos:], "<?")
+	if idx < 0 {
+		l.emit(phpTokInlineHTML, start, len(l.src))
+		l.advanceTo(len(l.src))
+		return
+	}
+	if idx > 0 {
+		l.emit(phpTokInlineHTML, start, start+idx)
+	}
+	l.advanceTo(start + idx + 2)
+	switch {
+	case strings.HasPrefix(strings.ToLower(l.src[l.pos:]), "php"):
+		l.advanceTo(l.pos + 3)
+	case strings.HasPrefix(l.src[l.pos:], "="):
+		l.advanceTo(l.pos + 1)
+	}
+	l.inPHP = true
+}
+
+func (l *phpLexer) lexPHP() {
+	c := l.src[l.pos]
+	switch {
+	case c == '\n':
+		l.line++
+		l.pos++
+	case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+		l.pos++
+	case strings.HasPrefix(l.src[l.pos:], "?>"):
+		start := l.pos
+		l.advanceTo(l.pos + 2)
+		l.tokens = append(l.tokens, phpToken{kind: phpTokPunct, text: ";", line: l.line, start: start, end: l.pos})
+		if l.pos < len(l.src) && l.src[l.pos] == '\n' {
+			l.advanceTo(l.pos + 1)
+		}
+		l.inPHP = false
+	case strings.HasPrefix(l.src[l.pos:], "#["):
+		l.emit(phpTokPunct, l.pos, l.pos+2)
+		l.advanceTo(l.pos + 2)
+	case c == '#' || strings.HasPrefix(l.src[l.pos:], "//"):
+		l.skipLineComment()
+	case strings.HasPrefix(l.src[l.pos:], "/*"):
+		end := strings.Index(l.src[l.pos+2:], "*/")
+		if end < 0 {
+			l.advanceTo(len(l.src))
+			return
+		}
+		l.advanceTo(l.pos + 2 + end + 2)
+	case c == '$' && l.pos+1 < len(l.src) && isPHPIdentStart(l.src[l.pos+1]):
+		start := l.pos
+		end := l.scanIdent(l.pos + 1)
+		l.emit(phpTokVariable, start, end)
+		l.advanceTo(end)
+	case isPHPIdentStart(c) || (c == '\\' && l.pos+1 < len(l.src) && isPHPIdentStart(l.src[l.pos+1])):
+		l.lexName()
+	case c >= '0' && c <= '9' || (c == '.' && l.pos+1 < len(l.src) && l.src[l.pos+1] >= '0' && l.src[l.pos+1] <= '9'):
+		l.lexNumber()
+	case c == '\'' || c == '"' || c == '`':
+		l.lexQuoted(c)
+	case strings.HasPrefix(l.src[l.pos:], "<<<"):
+		if !l.lexHeredoc() {
+			l.lexPunct()
+		}
+	default:
+		l.lexPunct()
+	}
+}
+
+func (l *phpLexer) skipLineComment() {
+	for l.pos < len(l.src) {
+		if l.src[l.pos] == '\n' || strings.HasPrefix(l.src[l.pos:], "?>") {
+			return
+		}
+		l.pos++
+	}
+}
+
+func (l *phpLexer) lexName() {
+	start := l.pos
+	pos := l.pos
+	for pos < len(l.src) {
+		if l.src[pos] == '\\' && pos+1 < len(l.src) && isPHPIdentStart(l.src[pos+1]) {
+			pos++
+			continue
+		}
+		if !isPHPIdentStart(l.src[pos]) {
+			break
+		}
+		pos = l.scanIdent(pos)
+		if pos >= len(l.src) || l.src[pos] != '\\' {
+			break
+		}
+	}
+	l.emit(phpTokIdent, start, pos)
+	l.advanceTo(pos)
+}
+
+func (l *phpLexer) lexNumber() {
+	start := l.pos
+	pos := l.pos
+	for pos < len(l.src) {
+		c := l.src[pos]
+		if isPHPIdentChar(c) || c == '.' {
+			pos++
+			continue
+		}
+		if (c == '+' || c == '-') && (l.src[pos-1] == 'e' || l.src[pos-1] == 'E') && !strings.HasPrefix(strings.ToLower(l.src[start:pos]), "0x") {
+			pos++
+			continue
+		}
+		break
+	}
+	l.emit(phpTokNumber, start, pos)
+	l.advanceTo(pos)
+}
+
+func (l *phpLexer) lexQuoted(quote byte) {
+	start := l.pos
+	pos := l.pos + 1
+	var value strings.Builder
+	for pos < len(l.src) && l.src[pos] != quote {
+		if l.src[pos] == '\\' && pos+1 < len(l.src) {
+			next := l.src[pos+1]
+			if quote == '\'' && next != '\\' && next != '\'' {
+				value.WriteByte('\\')
+			}
+			value.WriteByte(next)
+			pos += 2
+			continue
+		}
+		value.WriteByte(l.src[pos])
+		pos++
+	}
+	if pos < len(l.src) {
+		pos++
+	}
+	l.tokens = append(l.tokens, phpToken{kind: phpTokString, text: l.src[start:pos], value: value.String(), line: l.line, start: start, end: pos})
+	l.advanceTo(pos)
+}
+
+func (l *phpLexer) lexHeredoc() bool {
+	start := l.pos
+	pos := l.pos + 3
+	for pos < len(l.src) && (l.src[pos] == ' ' || l.src[pos] == '\t') {
+		pos++
+	}
+	quoted := pos < len(l.src) && (l.src[pos] == '\'' || l.src[pos] == '"')
+	if quoted {
+		pos++
+	}
+	if pos >= len(l.src) || !isPHPIdentStart(l.src[pos]) {
+		return false
+	}
+	idEnd := l.scanIdent(pos)
+	id := l.src[pos:idEnd]
+	pos = idEnd
+	if quoted {
+		pos++
+	}
+	nl := strings.IndexByte(l.src[pos:], '\n')
+	if nl < 0 {
+		return false
+	}
+	pos += nl + 1
+	bodyStart := pos
+	for pos < len(l.src) {
+		lineEnd := strings.IndexByte(l.src[pos:], '\n')
+		line := l.src[pos:]
+		if lineEnd >= 0 {
+			line = l.src[pos : pos+lineEnd]
+		}
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(trimmed, id) && (len(trimmed) == len(id) || !isPHPIdentChar(trimmed[len(id)])) {
+			end := pos + (len(line) - len(trimmed)) + len(id)
+			value := ""
+			if pos > bodyStart {
+				value = strings.TrimSuffix(l.src[bodyStart:pos], "\n")
+			}
+			l.tokens = append(l.tokens, phpToken{kind: phpTokString, text: l.src[start:end], value: value, line: l.line, start: start, end: end})
+			l.advanceTo(end)
+			return true
+		}
+		if lineEnd < 0 {
+			break
+		}
+		pos += lineEnd + 1
+	}
+	l.tokens = append(l.tokens, phpToken{kind: phpTokString, text: l.src[start:], line: l.line, start: start, end: len(l.src)})
+	l.advanceTo(len(l.src))
+	return true
+}
+
+func (l *phpLexer) lexPunct() {
+	for _, p := range phpPunctuators {
+		if strings.HasPrefix(l.src[l.pos:], p) {
+			l.emit(phpTokPunct, l.pos, l.pos+len(p))
+			l.advanceTo(l.pos + len(p))
+			return
+		}
+	}
+	l.emit(phpTokPunct, l.pos, l.pos+1)
+	l.advanceTo(l.pos + 1)
+}
+
+func (l *phpLexer) scanIdent(pos int) int {
+	for pos < len(l.src) && isPHPIdentChar(l.src[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func (l *phpLexer) emit(kind phpTokenKind, start, end int) {
+	l.tokens = append(l.tokens, phpToken{kind: kind, text: l.src[start:end], line: l.line, start: start, end: end})
+}
+
+// advanceTo moves the cursor forward while keeping the line counter in sync.
+func (l *phpLexer) advanceTo(pos int) {
+	if pos > len(l.src) {
+		pos = len(l.src)
+	}
+	l.line += strings.Count(l.src[l.pos:pos], "\n")
+	l.pos = pos
+}
+
+func isPHPIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isPHPIdentChar(c byte) bool {
+	return isPHPIdentStart(c) || (c >= '0' && c <= '9')
+}
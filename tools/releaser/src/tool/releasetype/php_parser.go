@@ -0,0 +1,684 @@
+package releasetype
+
+import (
+	"strings"
+)
+
+// phpFile is the symbol table extracted from a single PHP source file.
+type phpFile struct {
+	lines     []string
+	types     map[string]*phpType
+	functions map[string]*phpFunction
+	constants map[string]*phpConstant
+}
+
+type phpType struct {
+	kind       string
+	name       string
+	line       int
+	modifiers  []string
+	extends    []string
+	implements []string
+	traits     []string
+	methods    map[string]*phpFunction
+	properties map[string]*phpProperty
+	constants  map[string]*phpConstant
+	cases      map[string]*phpConstant
+}
+
+type phpFunction struct {
+	name       string
+	line       int
+	visibility string
+	static     bool
+	abstract   bool
+	final      bool
+	byRef      bool
+	params     []phpParam
+	returnType string
+}
+
+type phpParam struct {
+	name       string
+	typ        string
+	def        string
+	hasDefault bool
+	byRef      bool
+	variadic   bool
+	promoted   string
+	readonly   bool
+}
+
+type phpProperty struct {
+	name       string
+	line       int
+	visibility string
+	typ        string
+	def        string
+	static     bool
+	readonly   bool
+	promoted   bool
+}
+
+type phpConstant struct {
+	name       string
+	line       int
+	visibility string
+	typ        string
+	value      string
+	final      bool
+}
+
+// parsePHPSource builds a symbol table for src. Bodies of functions and
+// methods are skipped, so only the declared surface of the file is recorded.
+func parsePHPSource(src string) *phpFile {
+	file := &phpFile{
+		lines:     strings.Split(src, "\n"),
+		types:     make(map[string]*phpType),
+		functions: make(map[string]*phpFunction),
+		constants: make(map[string]*phpConstant),
+	}
+	p := &phpParser{src: src, toks: tokenizePHP(src), file: file, imports: make(map[string]string)}
+	p.parseTopLevel()
+	return file
+}
+
+type phpParser struct {
+	src       string
+	toks      []phpToken
+	pos       int
+	namespace string
+	imports   map[string]string
+	file      *phpFile
+}
+
+func (p *phpParser) parseTopLevel() {
+	var modifiers []string
+	for !p.eof() {
+		tok := p.peek()
+		if tok.kind != phpTokIdent {
+			if tok.text == "#[" {
+				p.skipAttribute()
+				continue
+			}
+			if tok.text == "{" && p.prevText() == ")" {
+				p.skipBlock()
+				continue
+			}
+			modifiers = nil
+			p.pos++
+			continue
+		}
+
+		word := strings.ToLower(tok.text)
+		switch {
+		case word == "namespace" && p.peekAt(1).kind == phpTokIdent:
+			p.pos++
+			p.namespace = strings.TrimPrefix(p.next().text, "\\")
+			p.imports = make(map[string]string)
+		case word == "use" && p.peekAt(1).text != "(":
+			p.pos++
+			p.parseUseStatement()
+		case word == "abstract" || word == "final" || word == "readonly":
+			modifiers = append(modifiers, word)
+			p.pos++
+			continue
+		case isPHPTypeKeyword(word) && p.isDeclarationStart():
+			line := tok.line
+			if len(modifiers) > 0 {
+				line = p.toks[p.pos-len(modifiers)].line
+			}
+			p.pos++
+			p.parseType(word, modifiers, line)
+		case word == "function" && p.isNamedFunction():
+			line := tok.line
+			p.pos++
+			fn := p.parseFunction(line)
+			fn.name = p.qualify(fn.name)
+			p.file.functions[strings.ToLower(fn.name)] = fn
+		case word == "const":
+			p.pos++
+			for _, c := range p.parseConstants("", tok.line) {
+				c.name = p.qualify(c.name)
+				p.file.constants[c.name] = c
+			}
+		case word == "new" && strings.EqualFold(p.peekAt(1).text, "class"):
+			p.pos += 2
+			p.skipUntilBlock()
+		default:
+			p.pos++
+		}
+		modifiers = nil
+	}
+}
+
+// isDeclarationStart reports whether the class/interface/trait/enum keyword at
+// the cursor opens a named declaration rather than e.g. `Foo::class`.
+func (p *phpParser) isDeclarationStart() bool {
+	prev := p.prevText()
+	if prev == "::" || prev == "->" || prev == "?->" || strings.EqualFold(prev, "new") {
+		return false
+	}
+	return p.peekAt(1).kind == phpTokIdent
+}
+
+func (p *phpParser) isNamedFunction() bool {
+	next := p.peekAt(1)
+	if next.text == "&" {
+		next = p.peekAt(2)
+	}
+	return next.kind == phpTokIdent
+}
+
+// parseUseStatement records class imports. Function and constant imports are
+// skipped, since they never name a type.
+func (p *phpParser) parseUseStatement() {
+	if w := strings.ToLower(p.peek().text); w == "function" || w == "const" {
+		p.skipExpression(";")
+		p.pos++
+		return
+	}
+	prefix := ""
+	for !p.eof() {
+		tok := p.next()
+		switch {
+		case tok.text == ";":
+			return
+		case tok.text == "{" || tok.text == "}" || tok.text == ",":
+			continue
+		case tok.kind == phpTokIdent:
+			name := strings.TrimPrefix(tok.text, "\\")
+			if p.peek().text == "\\" && p.peekAt(1).text == "{" {
+				prefix = name + "\\"
+				p.pos++
+				continue
+			}
+			name = prefix + name
+			alias := name
+			if idx := strings.LastIndex(alias, "\\"); idx >= 0 {
+				alias = alias[idx+1:]
+			}
+			if strings.EqualFold(p.peek().text, "as") {
+				p.pos++
+				alias = p.next().text
+			}
+			p.imports[strings.ToLower(alias)] = name
+		}
+	}
+}
+
+func (p *phpParser) parseType(kind string, modifiers []string, line int) {
+	t := &phpType{
+		kind:       kind,
+		name:       p.qualify(p.next().text),
+		line:       line,
+		modifiers:  modifiers,
+		methods:    make(map[string]*phpFunction),
+		properties: make(map[string]*phpProperty),
+		constants:  make(map[string]*phpConstant),
+		cases:      make(map[string]*phpConstant),
+	}
+
+	target := &t.extends
+	for !p.eof() && p.peek().text != "{" {
+		tok := p.next()
+		switch strings.ToLower(tok.text) {
+		case "extends":
+			target = &t.extends
+		case "implements":
+			target = &t.implements
+		case ",", ":":
+		default:
+			if tok.kind == phpTokIdent {
+				*target = append(*target, p.resolveName(tok.text))
+			}
+		}
+		if kind == "enum" && tok.text == ":" {
+			target = new([]string)
+		}
+	}
+	if p.eof() {
+		return
+	}
+	p.pos++
+	p.parseTypeBody(t)
+	p.file.types[strings.ToLower(t.name)] = t
+}
+
+func (p *phpParser) parseTypeBody(t *phpType) {
+	var modifiers []string
+	startLine := 0
+	for !p.eof() {
+		tok := p.peek()
+		if tok.text == "}" {
+			p.pos++
+			return
+		}
+		if tok.text == "#[" {
+			p.skipAttribute()
+			continue
+		}
+		if startLine == 0 {
+			startLine = tok.line
+		}
+
+		word := strings.ToLower(tok.text)
+		switch {
+		case tok.kind == phpTokIdent && word == "use":
+			p.pos++
+			t.traits = append(t.traits, p.parseTraitUse()...)
+		case tok.kind == phpTokIdent && word == "case" && t.kind == "enum":
+			p.pos++
+			c := &phpConstant{name: p.next().text, line: tok.line, visibility: "public"}
+			if p.peek().text == "=" {
+				p.pos++
+				c.value = p.skipExpression(";", ",")
+			}
+			p.expect(";")
+			t.cases[c.name] = c
+		case tok.kind == phpTokIdent && isPHPMemberModifier(word):
+			modifiers = append(modifiers, word)
+			p.pos++
+			continue
+		case tok.kind == phpTokIdent && word == "const":
+			p.pos++
+			for _, c := range p.parseConstants(memberVisibility(modifiers), startLine) {
+				c.final = containsString(modifiers, "final")
+				t.constants[c.name] = c
+			}
+		case tok.kind == phpTokIdent && word == "function":
+			p.pos++
+			fn := p.parseFunction(startLine)
+			fn.visibility = memberVisibility(modifiers)
+			fn.static = containsString(modifiers, "static")
+			fn.final = containsString(modifiers, "final")
+			fn.abstract = containsString(modifiers, "abstract") || t.kind == "interface"
+			t.methods[strings.ToLower(fn.name)] = fn
+			if strings.EqualFold(fn.name, "__construct") {
+				addPromotedProperties(t, fn)
+			}
+		case len(modifiers) > 0:
+			for _, prop := range p.parseProperties(startLine) {
+				prop.visibility = memberVisibility(modifiers)
+				prop.static = containsString(modifiers, "static")
+				prop.readonly = containsString(modifiers, "readonly") || containsString(t.modifiers, "readonly")
+				t.properties[prop.name] = prop
+			}
+		default:
+			p.pos++
+		}
+		modifiers = nil
+		startLine = 0
+	}
+}
+
+func (p *phpParser) parseTraitUse() []string {
+	var traits []string
+	for !p.eof() {
+		tok := p.next()
+		switch {
+		case tok.text == ";":
+			return traits
+		case tok.text == "{":
+			p.pos--
+			p.skipBlock()
+			return traits
+		case tok.kind == phpTokIdent:
+			traits = append(traits, p.resolveName(tok.text))
+		}
+	}
+	return traits
+}
+
+func (p *phpParser) parseConstants(visibility string, line int) []*phpConstant {
+	var out []*phpConstant
+	for !p.eof() {
+		typ := ""
+		if p.peek().kind == phpTokIdent && p.peekAt(1).text != "=" {
+			typ = p.parseTypeExpr()
+		}
+		name := p.next()
+		c := &phpConstant{name: name.text, line: line, visibility: visibility, typ: typ}
+		if p.peek().text == "=" {
+			p.pos++
+			c.value = p.skipExpression(";", ",")
+		}
+		out = append(out, c)
+		if p.peek().text != "," {
+			break
+		}
+		p.pos++
+	}
+	p.expect(";")
+	return out
+}
+
+func (p *phpParser) parseProperties(line int) []*phpProperty {
+	typ := ""
+	if p.peek().kind != phpTokVariable {
+		typ = p.parseTypeExpr()
+	}
+	var out []*phpProperty
+	for !p.eof() && p.peek().kind == phpTokVariable {
+		prop := &phpProperty{name: p.next().text, line: line, typ: typ}
+		if p.peek().text == "=" {
+			p.pos++
+			prop.def = p.skipExpression(";", ",", "{")
+		}
+		out = append(out, prop)
+		if p.peek().text != "," {
+			break
+		}
+		p.pos++
+	}
+	if p.peek().text == "{" {
+		p.skipBlock()
+		return out
+	}
+	p.expect(";")
+	return out
+}
+
+func (p *phpParser) parseFunction(line int) *phpFunction {
+	fn := &phpFunction{line: line, visibility: "public"}
+	if p.peek().text == "&" {
+		fn.byRef = true
+		p.pos++
+	}
+	fn.name = p.next().text
+	if p.peek().text == "(" {
+		p.pos++
+		fn.params = p.parseParams()
+	}
+	if p.peek().text == ":" {
+		p.pos++
+		fn.returnType = p.parseTypeExpr()
+	}
+	for !p.eof() {
+		switch p.peek().text {
+		case ";":
+			p.pos++
+			return fn
+		case "{":
+			p.skipBlock()
+			return fn
+		}
+		p.pos++
+	}
+	return fn
+}
+
+func (p *phpParser) parseParams() []phpParam {
+	var params []phpParam
+	for !p.eof() {
+		if p.peek().text == ")" {
+			p.pos++
+			return params
+		}
+		if p.peek().text == "#[" {
+			p.skipAttribute()
+			continue
+		}
+
+		param := phpParam{}
+		for p.peek().kind == phpTokIdent {
+			word := strings.ToLower(p.peek().text)
+			if word == "public" || word == "protected" || word == "private" {
+				param.promoted = word
+			} else if word == "readonly" {
+				param.readonly = true
+				if param.promoted == "" {
+					param.promoted = "public"
+				}
+			} else {
+				break
+			}
+			p.pos++
+		}
+		if p.peek().kind != phpTokVariable && p.peek().text != "&" && p.peek().text != "..." {
+			param.typ = p.parseTypeExpr()
+		}
+		if p.peek().text == "&" {
+			param.byRef = true
+			p.pos++
+		}
+		if p.peek().text == "..." {
+			param.variadic = true
+			p.pos++
+		}
+		if p.peek().kind == phpTokVariable {
+			param.name = p.next().text
+		}
+		if p.peek().text == "=" {
+			p.pos++
+			param.hasDefault = true
+			param.def = p.skipExpression(",", ")")
+		}
+		params = append(params, param)
+
+		switch p.peek().text {
+		case ",":
+			p.pos++
+		case ")":
+		default:
+			p.skipExpression(",", ")")
+			if p.peek().text == "," {
+				p.pos++
+			}
+		}
+	}
+	return params
+}
+
+// parseTypeExpr consumes a type declaration such as `?Foo`, `int|string` or
+// `(A&B)|null` and returns it with whitespace removed and names resolved.
+func (p *phpParser) parseTypeExpr() string {
+	var b strings.Builder
+	for !p.eof() {
+		tok := p.peek()
+		switch {
+		case tok.kind == phpTokIdent:
+			b.WriteString(p.resolveTypeName(tok.text))
+		case tok.text == "?" || tok.text == "|" || tok.text == "(" || tok.text == ")" && strings.Count(b.String(), "(") > strings.Count(b.String(), ")"):
+			b.WriteString(tok.text)
+		case tok.text == "&" && p.peekAt(1).kind != phpTokVariable && p.peekAt(1).text != "..." && p.peekAt(1).text != "&":
+			b.WriteString(tok.text)
+		default:
+			return b.String()
+		}
+		p.pos++
+	}
+	return b.String()
+}
+
+// skipExpression consumes tokens up to (not including) the first terminator
+// found outside of nested brackets and returns the consumed source text.
+func (p *phpParser) skipExpression(terminators ...string) string {
+	start := p.pos
+	depth := 0
+	for !p.eof() {
+		tok := p.peek()
+		if depth == 0 && containsString(terminators, tok.text) {
+			break
+		}
+		switch tok.text {
+		case "(", "[", "{", "#[":
+			depth++
+		case ")", "]", "}":
+			if depth == 0 {
+				return p.sourceText(start, p.pos)
+			}
+			depth--
+		}
+		p.pos++
+	}
+	return p.sourceText(start, p.pos)
+}
+
+func (p *phpParser) skipBlock() {
+	depth := 0
+	for !p.eof() {
+		switch p.next().text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth <= 0 {
+				return
+			}
+		}
+	}
+}
+
+func (p *phpParser) skipUntilBlock() {
+	for !p.eof() && p.peek().text != "{" {
+		if p.peek().text == "(" {
+			p.pos++
+			p.skipExpression(")")
+		}
+		p.pos++
+	}
+	p.skipBlock()
+}
+
+func (p *phpParser) skipAttribute() {
+	p.pos++
+	p.skipExpression("]")
+	p.expect("]")
+}
+
+func (p *phpParser) expect(text string) {
+	if !p.eof() && p.peek().text == text {
+		p.pos++
+	}
+}
+
+func (p *phpParser) sourceText(from, to int) string {
+	if from >= to || from >= len(p.toks) {
+		return ""
+	}
+	return strings.Join(strings.Fields(p.src[p.toks[from].start:p.toks[to-1].end]), " ")
+}
+
+func (p *phpParser) qualify(name string) string {
+	if p.namespace == "" {
+		return name
+	}
+	return p.namespace + "\\" + name
+}
+
+// resolveName turns a class reference into a fully qualified name using the
+// current namespace and `use` imports.
+func (p *phpParser) resolveName(name string) string {
+	if strings.HasPrefix(name, "\\") {
+		return strings.TrimPrefix(name, "\\")
+	}
+	head, rest, qualified := strings.Cut(name, "\\")
+	if imported, ok := p.imports[strings.ToLower(head)]; ok {
+		if qualified {
+			return imported + "\\" + rest
+		}
+		return imported
+	}
+	if strings.EqualFold(head, "namespace") && qualified {
+		return p.qualify(rest)
+	}
+	return p.qualify(name)
+}
+
+func (p *phpParser) resolveTypeName(name string) string {
+	if isPHPBuiltinType(name) {
+		return strings.ToLower(name)
+	}
+	return p.resolveName(name)
+}
+
+func (p *phpParser) eof() bool {
+	return p.pos >= len(p.toks)
+}
+
+func (p *phpParser) peek() phpToken {
+	return p.peekAt(0)
+}
+
+func (p *phpParser) peekAt(offset int) phpToken {
+	if p.pos+offset >= len(p.toks) {
+		return phpToken{}
+	}
+	return p.toks[p.pos+offset]
+}
+
+func (p *phpParser) next() phpToken {
+	tok := p.peek()
+	if !p.eof() {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *phpParser) prevText() string {
+	if p.pos == 0 {
+		return ""
+	}
+	return p.toks[p.pos-1].text
+}
+
+func addPromotedProperties(t *phpType, ctor *phpFunction) {
+	for _, param := range ctor.params {
+		if param.promoted == "" {
+			continue
+		}
+		t.properties[param.name] = &phpProperty{
+			name:       param.name,
+			line:       ctor.line,
+			visibility: param.promoted,
+			typ:        param.typ,
+			def:        param.def,
+			readonly:   param.readonly || containsString(t.modifiers, "readonly"),
+			promoted:   true,
+		}
+	}
+}
+
+func memberVisibility(modifiers []string) string {
+	for _, m := range modifiers {
+		if m == "public" || m == "protected" || m == "private" {
+			return m
+		}
+	}
+	return "public"
+}
+
+func isPHPTypeKeyword(word string) bool {
+	switch word {
+	case "class", "interface", "trait", "enum":
+		return true
+	}
+	return false
+}
+
+func isPHPMemberModifier(word string) bool {
+	switch word {
+	case "public", "protected", "private", "static", "abstract", "final", "readonly", "var":
+		return true
+	}
+	return false
+}
+
+func isPHPBuiltinType(name string) bool {
+	switch strings.ToLower(name) {
+	case "array", "bool", "callable", "false", "float", "int", "iterable", "mixed", "never",
+		"null", "object", "parent", "self", "static", "string", "true", "void":
+		return true
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package releasetype
+
+import "testing"
+
+func TestParsePHPSource_CollectsNamespacedSymbols(t *testing.T) {
+	src := `<?php
+
+namespace App\Services;
+
+use App\Contracts\Gateway;
+use Illuminate\Support\{Collection, Str as Strings};
+
+#[Attribute(Attribute::TARGET_CLASS)]
+final class Billing extends Base implements Gateway
+{
+    use Concerns\Loggable;
+
+    public const VERSION = '1.0';
+    protected const SECRET = 'x';
+
+    public ?Collection $items = null, $other;
+    private static array $cache = [];
+
+    public function __construct(
+        #[Inject] private readonly Strings $strings,
+        public int $retries = 3,
+    ) {
+        $handler = function () use ($retries) {
+            return new class {
+                public function ignored() {}
+            };
+        };
+    }
+
+    public static function &charge(int|float $amount, Gateway ...$gateways): static
+    {
+        $html = <<<HTML
+            <div>{ not a brace }</div>
+            HTML;
+        return $this;
+    }
+
+    abstract protected function hidden();
+}
+
+function helper(string $value = 'a,b)'): string { return $value; }
+`
+	file := parsePHPSource(src)
+
+	billing := file.types[`app\services\billing`]
+	if billing == nil {
+		t.Fatalf("expected Billing class, got %v", file.types)
+	}
+	if billing.kind != "class" || !containsString(billing.modifiers, "final") {
+		t.Fatalf("unexpected class header: %+v", billing)
+	}
+	if len(billing.extends) != 1 || billing.extends[0] != `App\Services\Base` {
+		t.Fatalf("unexpected extends: %v", billing.extends)
+	}
+	if len(billing.implements) != 1 || billing.implements[0] != `App\Contracts\Gateway` {
+		t.Fatalf("unexpected implements: %v", billing.implements)
+	}
+	if c := billing.constants["SECRET"]; c == nil || c.visibility != "protected" {
+		t.Fatalf("expected protected SECRET constant, got %+v", c)
+	}
+	if prop := billing.properties["$items"]; prop == nil || prop.typ != `?Illuminate\Support\Collection` {
+		t.Fatalf("expected typed $items property, got %+v", prop)
+	}
+	if _, ok := billing.properties["$other"]; !ok {
+		t.Fatalf("expected $other property from grouped declaration")
+	}
+	if prop := billing.properties["$strings"]; prop == nil || !prop.promoted || prop.visibility != "private" || !prop.readonly {
+		t.Fatalf("expected promoted readonly $strings property, got %+v", prop)
+	}
+	if prop := billing.properties["$retries"]; prop == nil || prop.def != "3" {
+		t.Fatalf("expected promoted $retries property with default, got %+v", prop)
+	}
+	if _, ok := billing.methods["ignored"]; ok {
+		t.Fatalf("anonymous class methods must not leak into the enclosing class")
+	}
+
+	charge := billing.methods["charge"]
+	if charge == nil {
+		t.Fatalf("expected charge method")
+	}
+	if got := charge.signature(); got != `public static function &charge(int|float $amount, App\Contracts\Gateway ...$gateways): static` {
+		t.Fatalf("unexpected signature: %s", got)
+	}
+	if hidden := billing.methods["hidden"]; hidden == nil || hidden.visibility != "protected" || !hidden.abstract {
+		t.Fatalf("expected abstract protected hidden method, got %+v", hidden)
+	}
+
+	helper := file.functions[`app\services\helper`]
+	if helper == nil || len(helper.params) != 1 || helper.params[0].def != `'a,b)'` {
+		t.Fatalf("expected helper function with string default, got %+v", helper)
+	}
+}
+
+func TestParsePHPSource_EnumsAndInterfaces(t *testing.T) {
+	src := `<?php
+enum Status: string implements HasLabel
+{
+    case Active = 'active';
+    case Archived = 'archived';
+
+    const DEFAULT = self::Active;
+
+    public function label(): string { return ucfirst($this->value); }
+}
+
+interface HasLabel
+{
+    public function label(): string;
+}
+`
+	file := parsePHPSource(src)
+
+	status := file.types["status"]
+	if status == nil || status.kind != "enum" {
+		t.Fatalf("expected Status enum, got %+v", status)
+	}
+	if len(status.cases) != 2 || status.cases["Archived"].value != "'archived'" {
+		t.Fatalf("unexpected enum cases: %+v", status.cases)
+	}
+	if len(status.implements) != 1 || status.implements[0] != "HasLabel" {
+		t.Fatalf("unexpected implements: %v", status.implements)
+	}
+	if _, ok := status.constants["DEFAULT"]; !ok {
+		t.Fatalf("expected DEFAULT constant")
+	}
+
+	iface := file.types["haslabel"]
+	if iface == nil || !iface.methods["label"].abstract {
+		t.Fatalf("expected interface methods to be abstract")
+	}
+}
@@ -1,242 +1,328 @@
 package releasetype
 
 import (
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
-	"releaser/tool/gitops"
 	"releaser/tool/output"
-	"releaser/tool/shared"
 )
 
-var (
-	rePublicFunction     = regexp.MustCompile(`^\s*public\s+function\s+([A-Za-z0-9_]+)\s*\((.*)\)`)
-	rePublicFunctionName = regexp.MustCompile(`^\s*public\s+function\s+([A-Za-z0-9_]+)\s*\(`)
-	reTypeDecl           = regexp.MustCompile(`\b(class|interface|trait|enum)\s+[A-Za-z0-9_]+`)
-	reTypeDeclNamed      = regexp.MustCompile(`\b(class|interface|trait|enum)\s+([A-Za-z0-9_]+)`)
-	rePublicProperty     = regexp.MustCompile(`public\s+\$[A-Za-z0-9_]+`)
-	rePublicConst        = regexp.MustCompile(`public\s+const\s+[A-Za-z0-9_]+`)
-	reVisibilityFunction = regexp.MustCompile(`(public|protected|private)\s+function\s+([A-Za-z0-9_]+)`)
-)
+func init() {
+	register(phpAnalyzer{})
+}
+
+// phpAnalyzer compares the declared API surface of PHP classes and functions.
+type phpAnalyzer struct{}
 
-type phpDiff struct {
-	removed []string
-	added   []string
+func (phpAnalyzer) Name() string {
+	return "php"
 }
 
-type signatureLine struct {
-	params string
-	line   string
+func (phpAnalyzer) Match(file string) bool {
+	return strings.HasSuffix(file, ".php") &&
+		!strings.HasPrefix(file, "tests/") &&
+		!isMigrationFile(file) &&
+		!isRouteFile(file) &&
+		!isConfigFile(file) &&
+		!isViewFile(file)
 }
 
-type visibilityLine struct {
-	visibility string
-	line       string
+func (phpAnalyzer) Analyze(r diffRange, files []string) *releaseSignals {
+	signals := newReleaseSignals()
+	for _, file := range files {
+		output.VeryVerbose("Analyzing PHP file: " + file)
+		analyzePHPFile(r, file, signals)
+	}
+	return signals
 }
 
-func analyzePHPFile(cfg *shared.Config, file string, signals *releaseSignals) {
-	diffText, _ := gitops.Run(cfg.BaseDir, "diff", cfg.OldTag+"..HEAD", "--", file)
-	diff := parsePHPDiff(diffText)
-	output.VeryVerbose("PHP diff stats for " + file + ": added=" + strconv.Itoa(len(diff.added)) + " removed=" + strconv.Itoa(len(diff.removed)))
+func analyzePHPFile(r diffRange, file string, signals *releaseSignals) {
+	oldSrc, newSrc, err := r.readFiles(file)
+	if err != nil {
+		output.Warn("Failed to read " + file + ": " + err.Error())
+		return
+	}
 
-	changedMethods, sameSignatureMethods := detectSignatureChanges(file, diff, signals)
-	output.VeryVerbose("Method signature map sizes for " + file + ": changed=" + strconv.Itoa(len(changedMethods)) + " same-signature=" + strconv.Itoa(len(sameSignatureMethods)))
+	oldFile := parsePHPSource(oldSrc)
+	newFile := parsePHPSource(newSrc)
+	output.VeryVerbose("PHP symbols for " + file + ": " + oldFile.summary() + " -> " + newFile.summary())
 
-	evaluateAddedAPI(file, diff, changedMethods, sameSignatureMethods, signals)
-	evaluateRemovedAPI(file, diff, changedMethods, sameSignatureMethods, signals)
+	opts := phpCompatOptions{namedArguments: r.Project.PHP.NamedArguments}
+	diffPHPSymbols(file, oldFile, newFile, opts, signals)
 	evaluateControllerRule(file, signals)
 }
 
-func parsePHPDiff(raw string) phpDiff {
-	d := phpDiff{}
-	for _, line := range strings.Split(raw, "\n") {
+func diffPHPSymbols(file string, oldFile, newFile *phpFile, opts phpCompatOptions, signals *releaseSignals) {
+	for _, key := range unionKeys(oldFile.types, newFile.types) {
+		oldType, newType := oldFile.types[key], newFile.types[key]
 		switch {
-		case strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---"):
-			d.removed = append(d.removed, strings.TrimPrefix(line, "-"))
-		case strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++"):
-			d.added = append(d.added, strings.TrimPrefix(line, "+"))
+		case newType == nil:
+			markMajorForFile(signals, file, "removed "+oldType.kind, oldFile.snippet(oldType.line, "- "))
+		case oldType == nil:
+			markMinorForFile(signals, file, "added "+newType.kind, newFile.snippet(newType.line, "+ "))
+		default:
+			diffPHPType(file, oldFile, newFile, oldType, newType, opts, signals)
 		}
 	}
-	return d
-}
 
-func detectSignatureChanges(file string, diff phpDiff, signals *releaseSignals) (map[string]bool, map[string]bool) {
-	changedMethods := map[string]bool{}
-	sameSignatureMethods := map[string]bool{}
-	addedSignatures := collectAddedSignatures(diff.added)
+	for _, key := range unionKeys(oldFile.functions, newFile.functions) {
+		oldFn, newFn := oldFile.functions[key], newFile.functions[key]
+		switch {
+		case newFn == nil:
+			markMajorForFile(signals, file, "removed function", "- "+oldFn.signature())
+		case oldFn == nil:
+			markMinorForFile(signals, file, "added function", "+ "+newFn.signature())
+		default:
+			diffPHPFunction(file, oldFn, newFn, opts, signals)
+		}
+	}
 
-	for _, removedLine := range diff.removed {
-		name, oldParams, ok := extractPublicSignature(removedLine)
-		if !ok {
-			continue
+	for _, key := range unionKeys(oldFile.constants, newFile.constants) {
+		oldConst, newConst := oldFile.constants[key], newFile.constants[key]
+		switch {
+		case newConst == nil:
+			markMajorForFile(signals, file, "removed constant", oldFile.snippetLine(oldConst.line, "- "))
+		case oldConst == nil:
+			markMinorForFile(signals, file, "added constant", newFile.snippetLine(newConst.line, "+ "))
 		}
+	}
+}
 
-		for _, addedSig := range addedSignatures[name] {
-			if oldParams != addedSig.params {
-				changedMethods[name] = true
-				sameSignatureMethods[name] = false
-				markMajorForFile(signals, file, "changed parameters for "+name, diffPairSnippet(removedLine, addedSig.line))
-			} else if !changedMethods[name] {
-				sameSignatureMethods[name] = true
-			}
+func diffPHPType(file string, oldFile, newFile *phpFile, oldType, newType *phpType, opts phpCompatOptions, signals *releaseSignals) {
+	name := shortPHPName(newType.name)
+	if oldType.kind != newType.kind {
+		markMajorForFile(signals, file, "changed "+name+" from "+oldType.kind+" to "+newType.kind, diffPairSnippet(oldFile.line(oldType.line), newFile.line(newType.line)))
+	}
+	for _, modifier := range []string{"abstract", "final", "readonly"} {
+		if containsString(newType.modifiers, modifier) && !containsString(oldType.modifiers, modifier) {
+			markMajorForFile(signals, file, "made "+name+" "+modifier, diffPairSnippet(oldFile.line(oldType.line), newFile.line(newType.line)))
+		}
+	}
+	for _, iface := range oldType.implements {
+		if !containsFold(newType.implements, iface) {
+			markMajorForFile(signals, file, name+" no longer implements "+shortPHPName(iface), diffPairSnippet(oldFile.line(oldType.line), newFile.line(newType.line)))
 		}
 	}
 
-	return changedMethods, sameSignatureMethods
-}
+	for _, key := range unionKeys(oldType.cases, newType.cases) {
+		oldCase, newCase := oldType.cases[key], newType.cases[key]
+		switch {
+		case newCase == nil:
+			markMajorForFile(signals, file, "removed enum case", oldFile.snippetLine(oldCase.line, "- "))
+		case oldCase == nil:
+			markMinorForFile(signals, file, "added enum case", newFile.snippetLine(newCase.line, "+ "))
+		case oldCase.value != newCase.value:
+			markMajorForFile(signals, file, "changed value of enum case "+key, diffPairSnippet(oldFile.line(oldCase.line), newFile.line(newCase.line)))
+		}
+	}
 
-func collectAddedSignatures(lines []string) map[string][]signatureLine {
-	out := make(map[string][]signatureLine)
-	for _, line := range lines {
-		name, params, ok := extractPublicSignature(line)
-		if !ok {
-			continue
+	for _, key := range unionKeys(oldType.constants, newType.constants) {
+		oldConst, newConst := oldType.constants[key], newType.constants[key]
+		oldPublic := oldConst != nil && oldConst.visibility == "public"
+		newPublic := newConst != nil && newConst.visibility == "public"
+		switch {
+		case oldPublic && newConst == nil:
+			markMajorForFile(signals, file, "removed public constant", oldFile.snippetLine(oldConst.line, "- "))
+		case oldPublic && !newPublic:
+			markMajorForFile(signals, file, "visibility changed for "+key, diffPairSnippet(oldFile.line(oldConst.line), newFile.line(newConst.line)))
+		case newPublic && !oldPublic:
+			markMinorForFile(signals, file, "added public constant", newFile.snippetLine(newConst.line, "+ "))
 		}
-		out[name] = append(out[name], signatureLine{params: params, line: line})
 	}
-	return out
-}
 
-func extractPublicSignature(line string) (name string, params string, ok bool) {
-	matches := rePublicFunction.FindStringSubmatch(line)
-	if len(matches) != 3 {
-		return "", "", false
+	for _, key := range unionKeys(oldType.properties, newType.properties) {
+		diffPHPProperty(file, oldFile, newFile, oldType.properties[key], newType.properties[key], signals)
+	}
+
+	for _, key := range unionKeys(oldType.methods, newType.methods) {
+		diffPHPMethod(file, newType, oldType.methods[key], newType.methods[key], opts, signals)
 	}
-	return matches[1], matches[2], true
 }
 
-func evaluateAddedAPI(file string, diff phpDiff, changedMethods, sameSignatureMethods map[string]bool, signals *releaseSignals) {
-	typeAdded := hasNetTypeAddition(diff)
-	if typeAdded {
-		output.VeryVerbose("Type declaration added in " + file + "; suppressing added-method entries for this file")
-	}
-	removedTypes := collectTypeDecls(diff.removed)
-
-	for i, line := range diff.added {
-		if reTypeDecl.MatchString(line) {
-			kind, name, _ := extractTypeDecl(line)
-			if _, exists := removedTypes[typeDeclKey(kind, name)]; exists {
-				output.VeryVerbose("Skipping added type declaration for " + kind + " " + name + " (declaration changed in place)")
-				continue
-			}
-			markMinorForFile(signals, file, "added "+kind, snippetBlock(diff.added, i, 4, "+ "))
+func diffPHPProperty(file string, oldFile, newFile *phpFile, oldProp, newProp *phpProperty, signals *releaseSignals) {
+	oldPublic := oldProp != nil && oldProp.visibility == "public"
+	newPublic := newProp != nil && newProp.visibility == "public"
+	switch {
+	case oldPublic && newProp == nil:
+		markMajorForFile(signals, file, "removed public property", oldFile.snippetLine(oldProp.line, "- "))
+	case oldPublic && !newPublic:
+		markMajorForFile(signals, file, "visibility changed for "+oldProp.name, diffPairSnippet(oldFile.line(oldProp.line), newFile.line(newProp.line)))
+	case newPublic && !oldPublic:
+		markMinorForFile(signals, file, "added public property", newFile.snippetLine(newProp.line, "+ "))
+	case oldPublic && newPublic:
+		snippet := diffPairSnippet(oldFile.line(oldProp.line), newFile.line(newProp.line))
+		if !strings.EqualFold(oldProp.typ, newProp.typ) {
+			markMajorForFile(signals, file, "changed type of property "+newProp.name, snippet)
 		}
-		if m := rePublicFunctionName.FindStringSubmatch(line); len(m) == 2 {
-			name := m[1]
-			if !typeAdded && !changedMethods[name] && !sameSignatureMethods[name] {
-				markMinorForFile(signals, file, "added public method", snippetBlock(diff.added, i, 4, "+ "))
-			}
+		if newProp.readonly && !oldProp.readonly {
+			markMajorForFile(signals, file, "made property "+newProp.name+" readonly", snippet)
 		}
-		if rePublicProperty.MatchString(line) {
-			markMinorForFile(signals, file, "added public property", snippetBlock(diff.added, i, 4, "+ "))
-		}
-		if rePublicConst.MatchString(line) {
-			markMinorForFile(signals, file, "added public constant", snippetBlock(diff.added, i, 4, "+ "))
+		if newProp.static != oldProp.static {
+			markMajorForFile(signals, file, "static modifier changed for property "+newProp.name, snippet)
 		}
 	}
 }
 
-func evaluateRemovedAPI(file string, diff phpDiff, changedMethods, sameSignatureMethods map[string]bool, signals *releaseSignals) {
-	addedVisibility := collectFunctionVisibilities(diff.added)
-	typeRemoved := hasNetTypeRemoval(diff)
-	if typeRemoved {
-		output.VeryVerbose("Type declaration removed in " + file + "; suppressing removed-method entries for this file")
-	}
-	addedTypes := collectTypeDecls(diff.added)
-
-	for i, line := range diff.removed {
-		if reTypeDecl.MatchString(line) {
-			kind, name, _ := extractTypeDecl(line)
-			if _, exists := addedTypes[typeDeclKey(kind, name)]; exists {
-				output.VeryVerbose("Skipping removed type declaration for " + kind + " " + name + " (declaration changed in place)")
-				continue
-			}
-			markMajorForFile(signals, file, "removed "+kind, snippetBlock(diff.removed, i, 4, "- "))
-		}
-
-		if m := rePublicFunctionName.FindStringSubmatch(line); len(m) == 2 {
-			name := m[1]
-			if !typeRemoved && !changedMethods[name] && !sameSignatureMethods[name] {
-				markMajorForFile(signals, file, "removed public method", snippetBlock(diff.removed, i, 4, "- "))
-			}
+func diffPHPMethod(file string, owner *phpType, oldFn, newFn *phpFunction, opts phpCompatOptions, signals *releaseSignals) {
+	oldPublic := oldFn != nil && oldFn.visibility == "public"
+	newPublic := newFn != nil && newFn.visibility == "public"
+	switch {
+	case oldPublic && newFn == nil:
+		markMajorForFile(signals, file, "removed public method", "- "+oldFn.signature())
+	case oldPublic && !newPublic:
+		markMajorForFile(signals, file, "visibility changed for "+newFn.name, diffPairSnippet(oldFn.signature(), newFn.signature()))
+	case newPublic && oldFn == nil:
+		switch {
+		case owner.kind == "interface":
+			markMajorForFile(signals, file, "added interface method", "+ "+newFn.signature())
+		case newFn.abstract:
+			markMajorForFile(signals, file, "added abstract method", "+ "+newFn.signature())
+		default:
+			markMinorForFile(signals, file, "added public method", "+ "+newFn.signature())
 		}
-
-		if m := reVisibilityFunction.FindStringSubmatch(line); len(m) == 3 {
-			oldVisibility := m[1]
-			name := m[2]
-			for _, newVisibility := range addedVisibility[name] {
-				if oldVisibility != newVisibility.visibility {
-					markMajorForFile(signals, file, "visibility changed for "+name, diffPairSnippet(line, newVisibility.line))
-				}
-			}
+	case newPublic && !oldPublic:
+		markMinorForFile(signals, file, "visibility changed for "+newFn.name, diffPairSnippet(oldFn.signature(), newFn.signature()))
+	case oldPublic && newPublic:
+		snippet := diffPairSnippet(oldFn.signature(), newFn.signature())
+		if oldFn.static != newFn.static {
+			markMajorForFile(signals, file, "static modifier changed for "+newFn.name, snippet)
 		}
-
-		if rePublicProperty.MatchString(line) {
-			markMajorForFile(signals, file, "removed public property", snippetBlock(diff.removed, i, 4, "- "))
+		if newFn.final && !oldFn.final {
+			markMajorForFile(signals, file, "made "+newFn.name+" final", snippet)
 		}
-		if rePublicConst.MatchString(line) {
-			markMajorForFile(signals, file, "removed public constant", snippetBlock(diff.removed, i, 4, "- "))
+		if newFn.abstract && !oldFn.abstract && owner.kind != "interface" {
+			markMajorForFile(signals, file, "made "+newFn.name+" abstract", snippet)
 		}
+		diffPHPFunction(file, oldFn, newFn, opts, signals)
 	}
 }
 
-func collectFunctionVisibilities(lines []string) map[string][]visibilityLine {
-	out := make(map[string][]visibilityLine)
-	for _, line := range lines {
-		m := reVisibilityFunction.FindStringSubmatch(line)
-		if len(m) != 3 {
-			continue
+func diffPHPFunction(file string, oldFn, newFn *phpFunction, opts phpCompatOptions, signals *releaseSignals) {
+	snippet := diffPairSnippet(oldFn.signature(), newFn.signature())
+	for _, verdict := range comparePHPSignatures(oldFn, newFn, opts) {
+		switch verdict.severity {
+		case "major":
+			markMajorForFile(signals, file, verdict.reason, snippet)
+		case "minor":
+			markMinorForFile(signals, file, verdict.reason, snippet)
+		default:
+			markPatchForFile(signals, file, verdict.reason, snippet)
 		}
-		out[m[2]] = append(out[m[2]], visibilityLine{visibility: m[1], line: line})
 	}
-	return out
 }
 
-func hasNetTypeAddition(diff phpDiff) bool {
-	addedTypes := collectTypeDecls(diff.added)
-	removedTypes := collectTypeDecls(diff.removed)
-	for key := range addedTypes {
-		if _, exists := removedTypes[key]; !exists {
-			return true
-		}
+// signature renders the declaration on a single line, regardless of how it
+// was formatted in the source.
+func (fn *phpFunction) signature() string {
+	var b strings.Builder
+	if fn.visibility != "" {
+		b.WriteString(fn.visibility + " ")
 	}
-	return false
+	if fn.abstract {
+		b.WriteString("abstract ")
+	}
+	if fn.final {
+		b.WriteString("final ")
+	}
+	if fn.static {
+		b.WriteString("static ")
+	}
+	b.WriteString("function ")
+	if fn.byRef {
+		b.WriteString("&")
+	}
+	b.WriteString(shortPHPName(fn.name))
+	b.WriteString("(" + renderPHPParams(fn.params) + ")")
+	if fn.returnType != "" {
+		b.WriteString(": " + fn.returnType)
+	}
+	return b.String()
 }
 
-func hasNetTypeRemoval(diff phpDiff) bool {
-	addedTypes := collectTypeDecls(diff.added)
-	removedTypes := collectTypeDecls(diff.removed)
-	for key := range removedTypes {
-		if _, exists := addedTypes[key]; !exists {
-			return true
-		}
+func renderPHPParams(params []phpParam) string {
+	parts := make([]string, 0, len(params))
+	for _, param := range params {
+		parts = append(parts, param.render())
 	}
-	return false
+	return strings.Join(parts, ", ")
 }
 
-func collectTypeDecls(lines []string) map[string]struct{} {
-	out := make(map[string]struct{})
-	for _, line := range lines {
-		kind, name, ok := extractTypeDecl(line)
-		if !ok {
-			continue
-		}
-		out[typeDeclKey(kind, name)] = struct{}{}
+func (param phpParam) render() string {
+	var b strings.Builder
+	if param.promoted != "" {
+		b.WriteString(param.promoted + " ")
+	}
+	if param.readonly {
+		b.WriteString("readonly ")
 	}
-	return out
+	if param.typ != "" {
+		b.WriteString(param.typ + " ")
+	}
+	if param.byRef {
+		b.WriteString("&")
+	}
+	if param.variadic {
+		b.WriteString("...")
+	}
+	b.WriteString(param.name)
+	if param.hasDefault {
+		b.WriteString(" = " + param.def)
+	}
+	return b.String()
 }
 
-func extractTypeDecl(line string) (kind string, name string, ok bool) {
-	m := reTypeDeclNamed.FindStringSubmatch(line)
-	if len(m) == 3 {
-		return m[1], m[2], true
+func (f *phpFile) summary() string {
+	methods := 0
+	for _, t := range f.types {
+		methods += len(t.methods)
+	}
+	return "types=" + strconv.Itoa(len(f.types)) + " methods=" + strconv.Itoa(methods) + " functions=" + strconv.Itoa(len(f.functions))
+}
+
+func (f *phpFile) line(line int) string {
+	if line <= 0 || line > len(f.lines) {
+		return ""
 	}
-	return "", "", false
+	return f.lines[line-1]
 }
 
-func typeDeclKey(kind, name string) string {
-	return kind + ":" + name
+func (f *phpFile) snippet(line int, prefix string) string {
+	return snippetBlock(f.lines, line-1, 4, prefix)
+}
+
+func (f *phpFile) snippetLine(line int, prefix string) string {
+	return snippetBlock(f.lines, line-1, 1, prefix)
+}
+
+func shortPHPName(name string) string {
+	if idx := strings.LastIndex(name, "\\"); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 func evaluateControllerRule(file string, signals *releaseSignals) {
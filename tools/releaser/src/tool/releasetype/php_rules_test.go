@@ -5,15 +5,11 @@ import (
 	"testing"
 )
 
-func TestEvaluateAddedAPI_SuppressesMethodWhenTypeAdded(t *testing.T) {
+func TestDiffPHPSymbols_SuppressesMethodWhenTypeAdded(t *testing.T) {
 	file := "app/Foo.php"
-	diff := phpDiff{added: []string{
-		"class Foo {",
-		"public function bar() {}",
-	}}
 	s := newReleaseSignals()
 
-	evaluateAddedAPI(file, diff, map[string]bool{}, map[string]bool{}, s)
+	diffPHPSymbols(file, parsePHPSource(""), parsePHPSource("<?php\nclass Foo {\n    public function bar() {}\n}\n"), phpCompatOptions{}, s)
 
 	rules := s.fileRules[file]
 	if len(rules) != 1 {
@@ -24,15 +20,11 @@ func TestEvaluateAddedAPI_SuppressesMethodWhenTypeAdded(t *testing.T) {
 	}
 }
 
-func TestEvaluateRemovedAPI_SuppressesMethodWhenTypeRemoved(t *testing.T) {
+func TestDiffPHPSymbols_SuppressesMethodWhenTypeRemoved(t *testing.T) {
 	file := "app/Foo.php"
-	diff := phpDiff{removed: []string{
-		"class Foo {",
-		"public function bar() {}",
-	}}
 	s := newReleaseSignals()
 
-	evaluateRemovedAPI(file, diff, map[string]bool{}, map[string]bool{}, s)
+	diffPHPSymbols(file, parsePHPSource("<?php\nclass Foo {\n    public function bar() {}\n}\n"), parsePHPSource(""), phpCompatOptions{}, s)
 
 	rules := s.fileRules[file]
 	if len(rules) != 1 {
@@ -43,39 +35,38 @@ func TestEvaluateRemovedAPI_SuppressesMethodWhenTypeRemoved(t *testing.T) {
 	}
 }
 
-func TestTypeDeclarationChangedInPlace_NoAddRemoveTypeFinding(t *testing.T) {
+func TestDiffPHPSymbols_TypeDeclarationChangedInPlace(t *testing.T) {
 	file := "app/Page.php"
-	diff := phpDiff{
-		added:   []string{"class Page extends NewBase {"},
-		removed: []string{"class Page extends OldBase {"},
-	}
 	s := newReleaseSignals()
 
-	evaluateAddedAPI(file, diff, map[string]bool{}, map[string]bool{}, s)
-	evaluateRemovedAPI(file, diff, map[string]bool{}, map[string]bool{}, s)
+	diffPHPSymbols(file, parsePHPSource("<?php\nclass Page extends OldBase {}\n"), parsePHPSource("<?php\nclass Page extends NewBase {}\n"), phpCompatOptions{}, s)
 
 	if len(s.fileRules[file]) != 0 {
 		t.Fatalf("expected no add/remove type findings for in-place type declaration change")
 	}
 }
 
-func TestDetectSignatureChanges_ReportsPairSnippet(t *testing.T) {
+func TestDiffPHPSymbols_AddedOptionalParameterReportsPairSnippet(t *testing.T) {
 	file := "app/Foo.php"
-	diff := phpDiff{
-		removed: []string{"public function run($a)"},
-		added:   []string{"public function run($a, $b = null)"},
-	}
 	s := newReleaseSignals()
 
-	detectSignatureChanges(file, diff, s)
+	diffPHPSymbols(file,
+		parsePHPSource("<?php\nclass Foo {\n    public function run($a) {}\n}\n"),
+		parsePHPSource("<?php\nclass Foo {\n    public function run($a, $b = null) {}\n}\n"),
+		phpCompatOptions{},
+		s,
+	)
 
-	if !s.major {
-		t.Fatalf("expected major signal")
+	if s.major || !s.minor {
+		t.Fatalf("expected a minor signal only, got major=%v minor=%v", s.major, s.minor)
 	}
 	rules := s.fileRules[file]
 	if len(rules) != 1 {
 		t.Fatalf("expected exactly 1 file rule, got %d", len(rules))
 	}
+	if rules[0].reason != "added optional parameter $b for run" {
+		t.Fatalf("unexpected reason %q", rules[0].reason)
+	}
 	if !strings.Contains(rules[0].snippet, "- public function run($a)") {
 		t.Fatalf("expected removed signature in snippet, got %q", rules[0].snippet)
 	}
@@ -84,6 +75,73 @@ func TestDetectSignatureChanges_ReportsPairSnippet(t *testing.T) {
 	}
 }
 
+func TestDiffPHPSymbols_MultiLineSignatureReformatIsNotAChange(t *testing.T) {
+	file := "app/Services/Billing.php"
+	s := newReleaseSignals()
+
+	diffPHPSymbols(file,
+		parsePHPSource("<?php\nclass Billing {\n    public function charge(int $amount, string $currency): bool {}\n}\n"),
+		parsePHPSource("<?php\nclass Billing {\n    #[Deprecated]\n    public function charge(\n        int $amount,\n        string $currency,\n    ): bool {\n    }\n}\n"),
+		phpCompatOptions{},
+		s,
+	)
+
+	if len(s.fileRules[file]) != 0 {
+		t.Fatalf("expected no findings, got %+v", s.fileRules[file])
+	}
+}
+
+func TestDiffPHPSymbols_RemovedPromotedProperty(t *testing.T) {
+	file := "app/Data/User.php"
+	s := newReleaseSignals()
+
+	diffPHPSymbols(file,
+		parsePHPSource("<?php\nclass User {\n    public function __construct(\n        public string $name,\n        public ?string $email = null,\n    ) {}\n}\n"),
+		parsePHPSource("<?php\nclass User {\n    public function __construct(\n        public string $name,\n    ) {}\n}\n"),
+		phpCompatOptions{},
+		s,
+	)
+
+	if !s.major {
+		t.Fatalf("expected major signal")
+	}
+	if !hasRuleReason(s.fileRules[file], "removed public property") {
+		t.Fatalf("expected removed public property rule, got %+v", s.fileRules[file])
+	}
+}
+
+func TestDiffPHPSymbols_VisibilityNarrowedIsMajor(t *testing.T) {
+	file := "app/Foo.php"
+	s := newReleaseSignals()
+
+	diffPHPSymbols(file,
+		parsePHPSource("<?php\nclass Foo {\n    public function run() {}\n}\n"),
+		parsePHPSource("<?php\nclass Foo {\n    protected function run() {}\n}\n"),
+		phpCompatOptions{},
+		s,
+	)
+
+	if !s.major || !hasRuleReason(s.fileRules[file], "visibility changed for run") {
+		t.Fatalf("expected visibility change to be major, got %+v", s.fileRules[file])
+	}
+}
+
+func TestDiffPHPSymbols_AddedInterfaceMethodIsMajor(t *testing.T) {
+	file := "app/Contracts/Gateway.php"
+	s := newReleaseSignals()
+
+	diffPHPSymbols(file,
+		parsePHPSource("<?php\ninterface Gateway {\n    public function pay(): void;\n}\n"),
+		parsePHPSource("<?php\ninterface Gateway {\n    public function pay(): void;\n    public function refund(): void;\n}\n"),
+		phpCompatOptions{},
+		s,
+	)
+
+	if !s.major || !hasRuleReason(s.fileRules[file], "added interface method") {
+		t.Fatalf("expected added interface method to be major, got %+v", s.fileRules[file])
+	}
+}
+
 func TestRenderSnippetCode_PreservesIndentation(t *testing.T) {
 	line := "    public function up(): void   "
 	got := renderSnippetCode(line, 120)
@@ -103,3 +161,12 @@ func TestSnippetBlock_UpToFourLines(t *testing.T) {
 		t.Fatalf("expected 4 lines, got %d", len(parts))
 	}
 }
+
+func hasRuleReason(rules []fileRule, reason string) bool {
+	for _, rule := range rules {
+		if rule.reason == reason {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,294 @@
+package releasetype
+
+import (
+	"strings"
+)
+
+// phpCall is a single `name(args)` link of a method chain such as
+// `$table->string('email')->nullable()`.
+type phpCall struct {
+	name      string
+	args      []string
+	argTokens [][]phpToken
+	line      int
+}
+
+// phpValue is a statically evaluated PHP expression. Arrays keep their
+// entries; everything else is represented by its source text.
+type phpValue struct {
+	raw      string
+	str      string
+	isString bool
+	isArray  bool
+	entries  []phpArrayEntry
+	line     int
+}
+
+type phpArrayEntry struct {
+	key    string
+	hasKey bool
+	value  *phpValue
+	line   int
+}
+
+// parsePHPCallChain reads `name(args)->name(args)...` starting at toks[start]
+// and returns the calls together with the index of the last consumed token.
+func parsePHPCallChain(toks []phpToken, start int) ([]phpCall, int) {
+	var calls []phpCall
+	i := start
+	for i < len(toks) && toks[i].kind == phpTokIdent {
+		call := phpCall{name: toks[i].text, line: toks[i].line}
+		if i+1 >= len(toks) || toks[i+1].text != "(" {
+			return calls, i
+		}
+		var end int
+		call.argTokens, end = parsePHPCallArgs(toks, i+1)
+		for _, arg := range call.argTokens {
+			call.args = append(call.args, phpArgText(arg))
+		}
+		calls = append(calls, call)
+		i = end + 1
+		if i >= len(toks) || (toks[i].text != "->" && toks[i].text != "?->" && toks[i].text != "::") {
+			return calls, end
+		}
+		i++
+	}
+	return calls, i
+}
+
+// parsePHPCallArgs splits the argument list opened at toks[open] into one token
+// slice per argument and returns the index of the closing parenthesis.
+func parsePHPCallArgs(toks []phpToken, open int) ([][]phpToken, int) {
+	var args [][]phpToken
+	var current []phpToken
+	depth := 0
+	flush := func() {
+		if len(current) > 0 {
+			args = append(args, current)
+		}
+		current = nil
+	}
+
+	for i := open; i < len(toks); i++ {
+		switch toks[i].text {
+		case "(", "[", "{", "#[":
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				flush()
+				return args, i
+			}
+		case ",":
+			if depth == 1 {
+				flush()
+				continue
+			}
+		}
+		current = append(current, toks[i])
+	}
+	flush()
+	return args, len(toks) - 1
+}
+
+// phpArgText renders an argument as its string value when it is a plain
+// string or a list of strings, and as its token text otherwise.
+func phpArgText(toks []phpToken) string {
+	if len(toks) == 1 && toks[0].kind == phpTokString {
+		return toks[0].value
+	}
+	if list, ok := phpStringList(toks); ok {
+		return strings.Join(list, ", ")
+	}
+	return joinPHPTokens(toks)
+}
+
+// phpStringList extracts the values of a literal list of strings such as
+// `['name', 'email']`.
+func phpStringList(toks []phpToken) ([]string, bool) {
+	if len(toks) == 1 && toks[0].kind == phpTokString {
+		return []string{toks[0].value}, true
+	}
+	if len(toks) < 2 || toks[0].text != "[" || toks[len(toks)-1].text != "]" {
+		return nil, false
+	}
+	var out []string
+	for _, tok := range toks[1 : len(toks)-1] {
+		switch {
+		case tok.kind == phpTokString:
+			out = append(out, tok.value)
+		case tok.text == ",":
+		default:
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+// parsePHPValue evaluates a literal expression. Array literals (`[...]` and
+// `array(...)`) are walked recursively; other expressions are kept as text.
+func parsePHPValue(toks []phpToken) *phpValue {
+	v := &phpValue{raw: joinPHPTokens(toks)}
+	if len(toks) == 0 {
+		return v
+	}
+	v.line = toks[0].line
+	if len(toks) == 1 && toks[0].kind == phpTokString {
+		v.isString = true
+		v.str = toks[0].value
+		return v
+	}
+
+	inner, ok := phpArrayLiteralBody(toks)
+	if !ok {
+		return v
+	}
+	v.isArray = true
+	for _, item := range splitPHPTokens(inner, ",") {
+		if len(item) == 0 {
+			continue
+		}
+		entry := phpArrayEntry{line: item[0].line}
+		parts := splitPHPTokens(item, "=>")
+		if len(parts) >= 2 {
+			entry.hasKey = true
+			entry.key = phpArgText(parts[0])
+			entry.value = parsePHPValue(item[len(parts[0])+1:])
+		} else {
+			entry.value = parsePHPValue(item)
+		}
+		v.entries = append(v.entries, entry)
+	}
+	return v
+}
+
+func phpArrayLiteralBody(toks []phpToken) ([]phpToken, bool) {
+	open := 0
+	if strings.EqualFold(toks[0].text, "array") && len(toks) > 1 && toks[1].text == "(" {
+		open = 1
+	} else if toks[0].text != "[" {
+		return nil, false
+	}
+
+	depth := 0
+	for i := open; i < len(toks); i++ {
+		switch toks[i].text {
+		case "(", "[", "{", "#[":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				if i != len(toks)-1 {
+					return nil, false
+				}
+				return toks[open+1 : i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// splitPHPTokens splits toks on sep occurring outside of nested brackets.
+func splitPHPTokens(toks []phpToken, sep string) [][]phpToken {
+	var parts [][]phpToken
+	depth := 0
+	start := 0
+	for i, tok := range toks {
+		switch tok.text {
+		case "(", "[", "{", "#[":
+			depth++
+		case ")", "]", "}":
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, toks[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, toks[start:])
+}
+
+// entry returns the value stored under key, if the array has one.
+func (v *phpValue) entry(key string) (*phpValue, bool) {
+	if v == nil {
+		return nil, false
+	}
+	for _, e := range v.entries {
+		if e.hasKey && e.key == key {
+			return e.value, true
+		}
+	}
+	return nil, false
+}
+
+// strings returns the string values of a string or a list of strings.
+func (v *phpValue) strings() []string {
+	if v == nil {
+		return nil
+	}
+	if v.isString {
+		return []string{v.str}
+	}
+	var out []string
+	for _, e := range v.entries {
+		if e.value != nil && e.value.isString {
+			out = append(out, e.value.str)
+		}
+	}
+	return out
+}
+
+func (c phpCall) arg(i int) string {
+	if i < len(c.args) {
+		return c.args[i]
+	}
+	return ""
+}
+
+// phpMethodBody returns the tokens between the braces of the first method
+// with the given name, or nil when it is not declared.
+func phpMethodBody(toks []phpToken, name string) []phpToken {
+	for i := 0; i+1 < len(toks); i++ {
+		if !strings.EqualFold(toks[i].text, "function") || !strings.EqualFold(toks[i+1].text, name) {
+			continue
+		}
+		for j := i + 2; j < len(toks); j++ {
+			if toks[j].text == ";" {
+				return nil
+			}
+			if toks[j].text == "{" {
+				return phpBlockBody(toks, j)
+			}
+		}
+	}
+	return nil
+}
+
+// phpBlockBody returns the tokens inside the brace block opened at toks[open].
+func phpBlockBody(toks []phpToken, open int) []phpToken {
+	depth := 0
+	for k := open; k < len(toks); k++ {
+		switch toks[k].text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return toks[open+1 : k]
+			}
+		}
+	}
+	return toks[open+1:]
+}
+
+func joinPHPTokens(toks []phpToken) string {
+	parts := make([]string, 0, len(toks))
+	for _, tok := range toks {
+		parts = append(parts, tok.text)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,411 @@
+package releasetype
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"releaser/tool/output"
+)
+
+type routeDef struct {
+	method     string
+	uri        string
+	name       string
+	middleware []string
+	namePrefix string
+	file       string
+	line       int
+}
+
+type routeGroup struct {
+	prefix     string
+	name       string
+	middleware []string
+}
+
+var routeVerbs = map[string]string{
+	"get":               "GET",
+	"post":              "POST",
+	"put":               "PUT",
+	"patch":             "PATCH",
+	"delete":            "DELETE",
+	"options":           "OPTIONS",
+	"any":               "ANY",
+	"view":              "GET",
+	"fallback":          "GET",
+	"redirect":          "ANY",
+	"permanentredirect": "ANY",
+}
+
+type resourceAction struct {
+	name   string
+	method string
+	suffix string
+	member bool
+}
+
+var resourceActions = []resourceAction{
+	{name: "index", method: "GET"},
+	{name: "create", method: "GET", suffix: "create"},
+	{name: "store", method: "POST"},
+	{name: "show", method: "GET", member: true},
+	{name: "edit", method: "GET", suffix: "edit", member: true},
+	{name: "update", method: "PUT|PATCH", member: true},
+	{name: "destroy", method: "DELETE", member: true},
+}
+
+func init() {
+	register(routeAnalyzer{})
+}
+
+// routeAnalyzer diffs the HTTP surface registered in routes/*.php.
+type routeAnalyzer struct{}
+
+func (routeAnalyzer) Name() string {
+	return "routes"
+}
+
+func (routeAnalyzer) Match(file string) bool {
+	return isRouteFile(file)
+}
+
+func (routeAnalyzer) Analyze(r diffRange, files []string) *releaseSignals {
+	signals := newReleaseSignals()
+	var oldRoutes, newRoutes []routeDef
+	for _, file := range files {
+		output.VeryVerbose("Analyzing route file: " + file)
+		oldSrc, newSrc, err := r.readFiles(file)
+		if err != nil {
+			output.Warn("Failed to read " + file + ": " + err.Error())
+			continue
+		}
+		oldRoutes = append(oldRoutes, parseRoutes(file, oldSrc)...)
+		newRoutes = append(newRoutes, parseRoutes(file, newSrc)...)
+	}
+	output.Verbose("Route surface: " + strconv.Itoa(len(oldRoutes)) + " routes at " + r.From + ", " + strconv.Itoa(len(newRoutes)) + " at " + r.To)
+	diffRoutes(oldRoutes, newRoutes, signals)
+	return signals
+}
+
+func isRouteFile(file string) bool {
+	return strings.HasPrefix(file, "routes/") && strings.HasSuffix(file, ".php")
+}
+
+// diffRoutes compares two route surfaces. Named routes are matched by name so
+// that re-pathing is detected; unnamed routes are matched by method and URI.
+func diffRoutes(oldRoutes, newRoutes []routeDef, signals *releaseSignals) {
+	newByName, newByPath := indexRoutes(newRoutes)
+	oldByName, oldByPath := indexRoutes(oldRoutes)
+
+	for _, old := range oldRoutes {
+		if old.name != "" {
+			current, ok := newByName[old.name]
+			switch {
+			case !ok && newByPath[old.key()] != nil:
+				markMajorForFile(signals, old.file, "removed route name "+old.name, "- "+old.describe())
+			case !ok:
+				markMajorForFile(signals, old.file, "removed route "+old.name, "- "+old.describe())
+			case current.key() != old.key():
+				markMajorForFile(signals, current.file, "changed route "+old.name, "- "+old.describe()+"\n+ "+current.describe())
+			default:
+				diffRouteMiddleware(old, current, signals)
+			}
+			continue
+		}
+
+		current, ok := newByPath[old.key()]
+		if !ok {
+			markMajorForFile(signals, old.file, "removed route "+old.key(), "- "+old.describe())
+			continue
+		}
+		diffRouteMiddleware(old, *current, signals)
+	}
+
+	for _, route := range newRoutes {
+		if route.name != "" {
+			if _, ok := oldByName[route.name]; ok {
+				continue
+			}
+			if previous := oldByPath[route.key()]; previous != nil {
+				if previous.name == "" {
+					markMinorForFile(signals, route.file, "added route name "+route.name, "+ "+route.describe())
+				}
+				continue
+			}
+		} else if _, ok := oldByPath[route.key()]; ok {
+			continue
+		}
+		markMinorForFile(signals, route.file, "added route "+route.key(), "+ "+route.describe())
+	}
+}
+
+func diffRouteMiddleware(old, current routeDef, signals *releaseSignals) {
+	if strings.Join(old.middleware, ",") == strings.Join(current.middleware, ",") {
+		return
+	}
+	markPatchForFile(signals, current.file, "changed middleware for "+current.key(), "- "+old.describe()+"\n+ "+current.describe())
+}
+
+func indexRoutes(routes []routeDef) (map[string]routeDef, map[string]*routeDef) {
+	byName := make(map[string]routeDef)
+	byPath := make(map[string]*routeDef)
+	for i := range routes {
+		if routes[i].name != "" {
+			byName[routes[i].name] = routes[i]
+		}
+		if _, exists := byPath[routes[i].key()]; !exists {
+			byPath[routes[i].key()] = &routes[i]
+		}
+	}
+	return byName, byPath
+}
+
+func (r routeDef) key() string {
+	return r.method + " " + r.uri
+}
+
+func (r routeDef) describe() string {
+	out := r.key()
+	if r.name != "" {
+		out += " (" + r.name + ")"
+	}
+	if len(r.middleware) > 0 {
+		out += " [" + strings.Join(r.middleware, ", ") + "]"
+	}
+	return out
+}
+
+// parseRoutes extracts the routes registered by a routes/*.php file,
+// expanding resources and applying group prefixes, names and middleware.
+func parseRoutes(file, src string) []routeDef {
+	return parseRouteStatements(file, tokenizePHP(src), routeGroup{})
+}
+
+func parseRouteStatements(file string, toks []phpToken, group routeGroup) []routeDef {
+	var routes []routeDef
+	for i := 0; i+1 < len(toks); i++ {
+		if !isRouteFacade(toks[i].text) || toks[i+1].text != "::" {
+			continue
+		}
+		calls, end := parsePHPCallChain(toks, i+2)
+		routes = append(routes, evaluateRouteChain(file, calls, group)...)
+		i = end
+	}
+	return routes
+}
+
+func evaluateRouteChain(file string, calls []phpCall, group routeGroup) []routeDef {
+	current := group
+	current.middleware = append([]string{}, group.middleware...)
+	for idx, call := range calls {
+		method := strings.ToLower(call.name)
+		switch method {
+		case "prefix":
+			current.prefix = joinRoutePath(current.prefix, call.arg(0))
+		case "name", "as":
+			current.name += call.arg(0)
+		case "middleware":
+			current.middleware = append(current.middleware, routeMiddleware(call)...)
+		case "group":
+			return evaluateRouteGroup(file, call, current)
+		case "resource", "apiresource", "singleton", "apisingleton":
+			return applyRouteModifiers(expandResource(file, call, method, current), calls[idx+1:])
+		case "resources", "apiresources":
+			var routes []routeDef
+			if value := parsePHPValue(firstArgTokens(call)); value.isArray {
+				for _, entry := range value.entries {
+					single := phpCall{name: call.name, args: []string{entry.key}, line: call.line}
+					routes = append(routes, expandResource(file, single, strings.TrimSuffix(method, "s"), current)...)
+				}
+			}
+			return routes
+		case "match":
+			verbs := strings.Split(strings.ToUpper(call.arg(0)), ", ")
+			sort.Strings(verbs)
+			route := newRoute(file, call, strings.Join(verbs, "|"), call.arg(1), current)
+			return applyRouteModifiers([]routeDef{route}, calls[idx+1:])
+		default:
+			if verb, ok := routeVerbs[method]; ok {
+				uri := call.arg(0)
+				if method == "fallback" {
+					uri = "{fallbackPlaceholder}"
+				}
+				route := newRoute(file, call, verb, uri, current)
+				return applyRouteModifiers([]routeDef{route}, calls[idx+1:])
+			}
+		}
+	}
+	return nil
+}
+
+// evaluateRouteGroup handles both `Route::group([...], fn)` and the fluent
+// `Route::prefix(...)->group(fn)` form.
+func evaluateRouteGroup(file string, call phpCall, group routeGroup) []routeDef {
+	if len(call.argTokens) == 0 {
+		return nil
+	}
+	if len(call.argTokens) > 1 {
+		attrs := parsePHPValue(call.argTokens[0])
+		if prefix, ok := attrs.entry("prefix"); ok {
+			group.prefix = joinRoutePath(group.prefix, prefix.str)
+		}
+		if as, ok := attrs.entry("as"); ok {
+			group.name += as.str
+		}
+		if middleware, ok := attrs.entry("middleware"); ok {
+			group.middleware = append(group.middleware, middleware.strings()...)
+		}
+	}
+
+	body := call.argTokens[len(call.argTokens)-1]
+	for i, tok := range body {
+		if tok.text == "{" {
+			return parseRouteStatements(file, phpBlockBody(body, i), group)
+		}
+		if tok.text == "=>" {
+			return parseRouteStatements(file, body[i+1:], group)
+		}
+	}
+	return nil
+}
+
+func applyRouteModifiers(routes []routeDef, calls []phpCall) []routeDef {
+	for _, call := range calls {
+		switch strings.ToLower(call.name) {
+		case "name":
+			if len(routes) == 1 {
+				if routes[0].name == "" {
+					routes[0].name = routes[0].namePrefix
+				}
+				routes[0].name += call.arg(0)
+			}
+		case "middleware":
+			for i := range routes {
+				routes[i].middleware = append(routes[i].middleware, routeMiddleware(call)...)
+			}
+		case "withoutmiddleware":
+			removed := routeMiddleware(call)
+			for i := range routes {
+				var kept []string
+				for _, m := range routes[i].middleware {
+					if !containsString(removed, m) {
+						kept = append(kept, m)
+					}
+				}
+				routes[i].middleware = kept
+			}
+		case "only", "except":
+			actions := routeMiddleware(call)
+			var kept []routeDef
+			for _, route := range routes {
+				action := route.name[strings.LastIndex(route.name, ".")+1:]
+				if containsString(actions, action) == strings.EqualFold(call.name, "only") {
+					kept = append(kept, route)
+				}
+			}
+			routes = kept
+		}
+	}
+	for i := range routes {
+		sort.Strings(routes[i].middleware)
+	}
+	return routes
+}
+
+func expandResource(file string, call phpCall, kind string, group routeGroup) []routeDef {
+	resource := call.arg(0)
+	segments := strings.Split(resource, ".")
+	var uri string
+	for i, segment := range segments {
+		uri = joinRoutePath(uri, segment)
+		if i < len(segments)-1 {
+			uri = joinRoutePath(uri, "{"+singularRouteParam(segment)+"}")
+		}
+	}
+	member := joinRoutePath(uri, "{"+singularRouteParam(segments[len(segments)-1])+"}")
+	singleton := strings.HasSuffix(kind, "singleton")
+	api := strings.HasPrefix(kind, "api")
+
+	var routes []routeDef
+	for _, action := range resourceActions {
+		if api && (action.name == "create" || action.name == "edit") {
+			continue
+		}
+		if singleton && (action.name == "index" || action.name == "store" || action.name == "destroy" || action.name == "create") {
+			continue
+		}
+		path := uri
+		if action.member && !singleton {
+			path = member
+		}
+		if action.suffix != "" {
+			path = joinRoutePath(path, action.suffix)
+		}
+		route := newRoute(file, call, action.method, path, group)
+		route.name = group.name + resource + "." + action.name
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+func newRoute(file string, call phpCall, method, uri string, group routeGroup) routeDef {
+	route := routeDef{
+		method:     method,
+		uri:        joinRoutePath(group.prefix, uri),
+		middleware: append([]string{}, group.middleware...),
+		namePrefix: group.name,
+		file:       file,
+		line:       call.line,
+	}
+	if route.uri == "" {
+		route.uri = "/"
+	}
+	sort.Strings(route.middleware)
+	return route
+}
+
+func routeMiddleware(call phpCall) []string {
+	var out []string
+	for _, arg := range call.argTokens {
+		out = append(out, parsePHPValue(arg).strings()...)
+	}
+	return out
+}
+
+func firstArgTokens(call phpCall) []phpToken {
+	if len(call.argTokens) == 0 {
+		return nil
+	}
+	return call.argTokens[0]
+}
+
+func joinRoutePath(prefix, uri string) string {
+	prefix = strings.Trim(prefix, "/")
+	uri = strings.Trim(uri, "/")
+	switch {
+	case prefix == "":
+		return uri
+	case uri == "":
+		return prefix
+	default:
+		return prefix + "/" + uri
+	}
+}
+
+func singularRouteParam(segment string) string {
+	segment = strings.ReplaceAll(segment, "-", "_")
+	switch {
+	case strings.HasSuffix(segment, "ies"):
+		return strings.TrimSuffix(segment, "ies") + "y"
+	case strings.HasSuffix(segment, "ses"):
+		return strings.TrimSuffix(segment, "es")
+	case strings.HasSuffix(segment, "s"):
+		return strings.TrimSuffix(segment, "s")
+	}
+	return segment
+}
+
+func isRouteFacade(name string) bool {
+	return name == "Route" || strings.HasSuffix(name, "\\Route")
+}
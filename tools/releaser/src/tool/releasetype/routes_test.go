@@ -0,0 +1,74 @@
+package releasetype
+
+import "testing"
+
+const apiRoutes = `<?php
+
+use App\Http\Controllers\PhotoController;
+use Illuminate\Support\Facades\Route;
+
+Route::get('/status', fn () => 'ok')->name('status');
+
+Route::prefix('v1')->name('v1.')->middleware(['auth:sanctum'])->group(function () {
+    Route::apiResource('photos', PhotoController::class)->only(['index', 'show']);
+    Route::match(['get', 'post'], 'search', [SearchController::class, 'run']);
+});
+
+Route::group(['prefix' => 'admin', 'as' => 'admin.', 'middleware' => 'can:admin'], function () {
+    Route::post('/users/{user}/ban', [UserController::class, 'ban'])->name('users.ban')->middleware('throttle:10,1');
+});
+`
+
+func TestParseRoutes_ExpandsGroupsAndResources(t *testing.T) {
+	routes := parseRoutes("routes/api.php", apiRoutes)
+
+	expected := []string{
+		"GET status (status)",
+		"GET v1/photos (v1.photos.index) [auth:sanctum]",
+		"GET v1/photos/{photo} (v1.photos.show) [auth:sanctum]",
+		"GET|POST v1/search [auth:sanctum]",
+		"POST admin/users/{user}/ban (admin.users.ban) [can:admin, throttle:10,1]",
+	}
+	if len(routes) != len(expected) {
+		t.Fatalf("expected %d routes, got %d: %+v", len(expected), len(routes), routes)
+	}
+	for i, route := range routes {
+		if route.describe() != expected[i] {
+			t.Fatalf("route %d: expected %q, got %q", i, expected[i], route.describe())
+		}
+	}
+}
+
+func TestDiffRoutes_Classification(t *testing.T) {
+	oldRoutes := parseRoutes("routes/web.php", `<?php
+Route::get('/posts', [PostController::class, 'index'])->name('posts.index');
+Route::get('/about', AboutController::class);
+Route::get('/legacy', LegacyController::class);
+Route::get('/dashboard', DashboardController::class)->name('dashboard');
+`)
+	newRoutes := parseRoutes("routes/web.php", `<?php
+Route::get('/articles', [PostController::class, 'index'])->name('posts.index');
+Route::get('/about', AboutController::class);
+Route::get('/dashboard', DashboardController::class)->name('dashboard')->middleware('auth');
+Route::get('/contact', ContactController::class);
+`)
+	s := newReleaseSignals()
+
+	diffRoutes(oldRoutes, newRoutes, s)
+
+	rules := s.fileRules["routes/web.php"]
+	expected := map[string]string{
+		"changed route posts.index":            "major",
+		"removed route GET legacy":             "major",
+		"changed middleware for GET dashboard": "patch",
+		"added route GET contact":              "minor",
+	}
+	if len(rules) != len(expected) {
+		t.Fatalf("expected %d rules, got %+v", len(expected), rules)
+	}
+	for _, rule := range rules {
+		if expected[rule.reason] != rule.severity {
+			t.Fatalf("unexpected rule %+v", rule)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"releaser/tool/output"
+	"releaser/tool/releasetype"
+	"releaser/tool/shared"
+	"releaser/tool/version"
+)
+
+const FileName = ".releaser.json"
+
+// Load reads the optional project settings file from the base directory. A
+// missing file leaves the defaults untouched.
+func Load(cfg *shared.Config) error {
+	path := filepath.Join(cfg.BaseDir, FileName)
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		output.Verbose("No " + FileName + " found; using default project settings")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to read %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(b, &cfg.Project); err != nil {
+		return fmt.Errorf("Failed to parse %s: %w", path, err)
+	}
+	switch cfg.Project.Commits.Precedence {
+	case "":
+		cfg.Project.Commits.Precedence = shared.PrecedenceMax
+	case shared.PrecedenceMax, shared.PrecedenceCommits, shared.PrecedenceDiff:
+	default:
+		return fmt.Errorf("Invalid commits.precedence %q in %s (expected max, commits or diff)", cfg.Project.Commits.Precedence, path)
+	}
+	if err := validateVersion(cfg.Project.Version, "version", path); err != nil {
+		return err
+	}
+	if err := validateVersionFiles(cfg.Project.VersionFiles, path); err != nil {
+		return err
+	}
+	if err := validateComponents(cfg.Project.Components, path); err != nil {
+		return err
+	}
+	for name := range cfg.Project.Prompts {
+		switch name {
+		case shared.PromptReleaseType, shared.PromptConfirmRelease, shared.PromptConfirmRollback:
+		default:
+			return fmt.Errorf("Unknown prompt %q in %s (expected release_type, confirm_release or confirm_rollback)", name, path)
+		}
+	}
+	output.Verbose("Project settings loaded from " + path)
+	return nil
+}
+
+func validateVersion(settings shared.VersionSettings, key, path string) error {
+	switch settings.Source {
+	case "", shared.SourceTags, shared.SourceGitHub:
+	default:
+		return fmt.Errorf("Invalid %s.source %q in %s (expected tags or github)", key, settings.Source, path)
+	}
+	if _, err := version.SchemeFor(settings); err != nil {
+		return fmt.Errorf("Invalid %s settings in %s: %w", key, path, err)
+	}
+	return nil
+}
+
+func validateVersionFiles(files []shared.VersionFile, path string) error {
+	for i, file := range files {
+		if file.Path == "" {
+			return fmt.Errorf("Missing path for version_files[%d] in %s", i, path)
+		}
+		switch file.Type {
+		case "", shared.VersionFileJSON, shared.VersionFilePHPConst, shared.VersionFilePHPArray, shared.VersionFilePlain:
+		case shared.VersionFileRegex:
+			if file.Pattern == "" {
+				return fmt.Errorf("Missing pattern for regex version file %s in %s", file.Path, path)
+			}
+		default:
+			return fmt.Errorf("Invalid type %q for version file %s in %s (expected json, php-const, php-array, plain or regex)", file.Type, file.Path, path)
+		}
+	}
+	return nil
+}
+
+// validateComponents checks the monorepo components and fills in their
+// default tag prefix.
+func validateComponents(components []shared.Component, path string) error {
+	analyzers := make(map[string]bool)
+	for _, name := range releasetype.AnalyzerNames() {
+		analyzers[name] = true
+	}
+	seen := make(map[string]bool)
+	for i := range components {
+		component := &components[i]
+		if component.Name == "" || component.Path == "" {
+			return fmt.Errorf("Missing name or path for components[%d] in %s", i, path)
+		}
+		if seen[component.Name] {
+			return fmt.Errorf("Duplicate component %s in %s", component.Name, path)
+		}
+		seen[component.Name] = true
+		component.Path = filepath.ToSlash(filepath.Clean(component.Path))
+		if component.TagPrefix == "" {
+			component.TagPrefix = component.Name + "-v"
+		}
+		if component.Version.TagPrefix != "" {
+			return fmt.Errorf("Use tag_prefix instead of version.tag_prefix for component %s in %s", component.Name, path)
+		}
+		if err := validateVersion(component.Version, "components."+component.Name+".version", path); err != nil {
+			return err
+		}
+		if err := validateVersionFiles(component.VersionFiles, path); err != nil {
+			return err
+		}
+		for _, name := range component.Analyzers {
+			if !analyzers[name] {
+				return fmt.Errorf("Unknown analyzer %q for component %s in %s", name, component.Name, path)
+			}
+		}
+	}
+	return nil
+}
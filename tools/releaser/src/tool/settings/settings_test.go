@@ -0,0 +1,68 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"releaser/tool/shared"
+)
+
+func load(t *testing.T, content string) (*shared.Config, error) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &shared.Config{BaseDir: dir}
+	return cfg, Load(cfg)
+}
+
+func TestLoad_ComponentDefaults(t *testing.T) {
+	cfg, err := load(t, `{"components": [
+		{"name": "billing", "path": "./packages/billing/", "analyzers": ["php", "composer"]},
+		{"name": "web", "path": "apps/web", "tag_prefix": "web@"}
+	]}`)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	components := cfg.Project.Components
+	if components[0].Path != "packages/billing" || components[0].TagPrefix != "billing-v" {
+		t.Errorf("expected a cleaned path and default tag prefix, got %+v", components[0])
+	}
+	if components[1].TagPrefix != "web@" {
+		t.Errorf("expected the configured tag prefix to be kept, got %q", components[1].TagPrefix)
+	}
+}
+
+func TestLoad_InvalidComponents(t *testing.T) {
+	cases := []struct {
+		name       string
+		components string
+		want       string
+	}{
+		{name: "missing name", components: `[{"path": "packages/billing"}]`, want: "Missing name or path for components[0]"},
+		{name: "missing path", components: `[{"name": "billing"}]`, want: "Missing name or path for components[0]"},
+		{name: "duplicate", components: `[{"name": "billing", "path": "a"}, {"name": "billing", "path": "b"}]`, want: "Duplicate component billing"},
+		{name: "version tag prefix", components: `[{"name": "billing", "path": "a", "version": {"tag_prefix": "b-"}}]`, want: "Use tag_prefix instead of version.tag_prefix"},
+		{name: "unknown scheme", components: `[{"name": "billing", "path": "a", "version": {"scheme": "romver"}}]`, want: "Invalid components.billing.version settings"},
+		{name: "unknown source", components: `[{"name": "billing", "path": "a", "version": {"source": "npm"}}]`, want: "Invalid components.billing.version.source"},
+		{name: "unknown analyzer", components: `[{"name": "billing", "path": "a", "analyzers": ["cobol"]}]`, want: `Unknown analyzer "cobol" for component billing`},
+		{name: "regex without pattern", components: `[{"name": "billing", "path": "a", "version_files": [{"path": "VERSION", "type": "regex"}]}]`, want: "Missing pattern for regex version file VERSION"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := load(t, `{"components": `+c.components+`}`)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("expected an error containing %q, got %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestLoad_UnknownPrompt(t *testing.T) {
+	if _, err := load(t, `{"prompts": {"release_type": "default", "confirm_tag": "yes"}}`); err == nil || !strings.Contains(err.Error(), `Unknown prompt "confirm_tag"`) {
+		t.Fatalf("expected the unknown prompt to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package shared
+
+import (
+	"path"
+
+	"releaser/tool/plan"
+)
+
+// ForComponent returns a copy of the configuration scoped to one component:
+// its tag prefix, version settings, version files and changelog replace the
+// repository-wide ones.
+func (c *Config) ForComponent(component Component) *Config {
+	scoped := *c
+	scoped.Component = &component
+	scoped.Project.Version = component.Version
+	scoped.Project.Version.TagPrefix = component.TagPrefix
+	scoped.Project.VersionFiles = component.VersionFiles
+	scoped.Project.Changelog = component.Changelog
+	scoped.Rules = nil
+	scoped.ReleaseFiles = nil
+	if c.Plan != nil {
+		scoped.Plan = &plan.Plan{}
+	}
+	return &scoped
+}
+
+// ComponentPath returns the directory of the component being released, or
+// an empty string for the whole repository.
+func (c *Config) ComponentPath() string {
+	if c.Component == nil {
+		return ""
+	}
+	return c.Component.Path
+}
+
+// Pathspec limits git log and diff to the component being released; it is
+// empty for the whole repository.
+func (c *Config) Pathspec() []string {
+	if c.Component == nil {
+		return nil
+	}
+	return []string{c.Component.Path}
+}
+
+// RepoPath maps a path relative to the component to one relative to the
+// repository root.
+func (c *Config) RepoPath(file string) string {
+	if c.Component == nil {
+		return file
+	}
+	return path.Join(c.Component.Path, file)
+}
@@ -0,0 +1,86 @@
+package shared
+
+import (
+	"reflect"
+	"testing"
+
+	"releaser/tool/plan"
+)
+
+func TestForComponent(t *testing.T) {
+	cfg := &Config{
+		Type:         "minor",
+		Rules:        []Rule{{File: "src/a.php"}},
+		ReleaseFiles: []string{"CHANGELOG.md"},
+		Plan:         &plan.Plan{},
+		Project: Project{
+			Version:      VersionSettings{Scheme: SchemeSemVer, TagPrefix: "v"},
+			VersionFiles: []VersionFile{{Path: "package.json"}},
+			Changelog:    ChangelogSettings{Path: "CHANGELOG.md"},
+		},
+	}
+	component := Component{
+		Name:         "billing",
+		Path:         "packages/billing",
+		TagPrefix:    "billing-v",
+		Version:      VersionSettings{Scheme: SchemeCalVer},
+		VersionFiles: []VersionFile{{Path: "composer.json"}},
+		Changelog:    ChangelogSettings{Path: "HISTORY.md"},
+	}
+
+	scoped := cfg.ForComponent(component)
+	if scoped.Component == nil || scoped.Component.Name != "billing" {
+		t.Fatalf("expected the billing component, got %+v", scoped.Component)
+	}
+	if scoped.Project.Version.Scheme != SchemeCalVer || scoped.Project.Version.TagPrefix != "billing-v" {
+		t.Fatalf("unexpected version settings %+v", scoped.Project.Version)
+	}
+	if !reflect.DeepEqual(scoped.Project.VersionFiles, component.VersionFiles) || scoped.Project.Changelog.Path != "HISTORY.md" {
+		t.Fatalf("expected the component version files and changelog, got %+v", scoped.Project)
+	}
+	if scoped.Rules != nil || scoped.ReleaseFiles != nil {
+		t.Fatalf("expected per-release state to be reset, got %v %v", scoped.Rules, scoped.ReleaseFiles)
+	}
+	if scoped.Plan == nil || scoped.Plan == cfg.Plan {
+		t.Fatalf("expected a dry run component to record its own plan")
+	}
+	if scoped.Type != "minor" {
+		t.Fatalf("expected the release type to be kept, got %s", scoped.Type)
+	}
+	if cfg.Component != nil || cfg.Project.Version.TagPrefix != "v" || len(cfg.ReleaseFiles) != 1 {
+		t.Fatalf("expected the repository configuration to be left untouched, got %+v", cfg)
+	}
+	if cfg.ForComponent(component).Plan == nil || (&Config{}).ForComponent(component).Plan != nil {
+		t.Fatalf("expected a plan only for dry runs")
+	}
+}
+
+func TestComponentPaths(t *testing.T) {
+	cases := []struct {
+		name      string
+		component *Component
+		file      string
+		repoPath  string
+		root      string
+		pathspec  []string
+	}{
+		{name: "repository", file: "CHANGELOG.md", repoPath: "CHANGELOG.md"},
+		{name: "component", component: &Component{Name: "billing", Path: "packages/billing"}, file: "CHANGELOG.md", repoPath: "packages/billing/CHANGELOG.md", root: "packages/billing", pathspec: []string{"packages/billing"}},
+		{name: "nested file", component: &Component{Name: "web", Path: "apps/web"}, file: "src/version.ts", repoPath: "apps/web/src/version.ts", root: "apps/web", pathspec: []string{"apps/web"}},
+		{name: "parent reference", component: &Component{Name: "web", Path: "apps/web"}, file: "../shared/VERSION", repoPath: "apps/shared/VERSION", root: "apps/web", pathspec: []string{"apps/web"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &Config{Component: c.component}
+			if got := cfg.RepoPath(c.file); got != c.repoPath {
+				t.Errorf("RepoPath(%q) = %q, want %q", c.file, got, c.repoPath)
+			}
+			if got := cfg.ComponentPath(); got != c.root {
+				t.Errorf("ComponentPath() = %q, want %q", got, c.root)
+			}
+			if got := cfg.Pathspec(); !reflect.DeepEqual(got, c.pathspec) {
+				t.Errorf("Pathspec() = %v, want %v", got, c.pathspec)
+			}
+		})
+	}
+}
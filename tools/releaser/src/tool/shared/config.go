@@ -1,19 +1,69 @@
 package shared
 
+import (
+	"releaser/tool/journal"
+	"releaser/tool/plan"
+)
+
 type Config struct {
-	Type      string
-	TypeSet   bool
-	Force     bool
-	Follow    bool
-	Verbosity int
-	BaseDir   string
-	Token     string
-	Repo      string
-	OldTag    string
-	OldVer    string
-	NewVer    string
-	NewTag    string
-	Changes   string
-	Release   string
-	Published string
+	Type           string
+	TypeSet        bool
+	PreID          string
+	Force          bool
+	Yes            bool
+	Follow         bool
+	DryRun         bool
+	NonInteractive bool
+	Verbosity      int
+	BaseDir        string
+	Token          string
+	Repo           string
+	OldTag         string
+	TagPrefix      string
+	OldVer         string
+	NewVer         string
+	NewTag         string
+	Prerelease     bool
+	Changes        string
+	Release        string
+	Published      string
+	Project        Project
+	// Rules lists the file-level findings behind the detected release type,
+	// for use in release notes templates.
+	Rules []Rule
+	// ReleaseFiles are the files updated for the release, relative to the
+	// repository root, committed before tagging.
+	ReleaseFiles []string
+	// Components are the monorepo components selected on the command line;
+	// AllComponents releases every changed component.
+	Components    []string
+	AllComponents bool
+	// Component is the component being released, nil outside monorepos.
+	Component *Component
+	// DependencyBumps maps the package names of components released earlier
+	// in the same run to their new version.
+	DependencyBumps map[string]string
+	// Command is the subcommand: empty for a release, "plan", "apply" or
+	// "rollback";
+	// PlanFile is the plan it writes or executes.
+	Command  string
+	PlanFile string
+	// RollbackTag is the release undone by rollback, the latest one when
+	// empty; Revert also reverts its release commit.
+	RollbackTag string
+	Revert      bool
+	// Plan records the mutations of a --dry-run instead of performing them;
+	// it is nil otherwise.
+	Plan *plan.Plan
+	// Journal tracks the completed steps of each release so a failed one
+	// can resume; it is nil during dry runs and plan/apply.
+	Journal *journal.Journal
+}
+
+// Rule is one finding of the release type detection.
+type Rule struct {
+	File     string
+	Severity string
+	Reason   string
+	Snippet  string
 }
@@ -0,0 +1,141 @@
+package shared
+
+// Project holds the repository-local settings read from .releaser.json.
+type Project struct {
+	PHP       PHPSettings       `json:"php"`
+	Commits   CommitSettings    `json:"commits"`
+	Notes     NotesSettings     `json:"notes"`
+	Changelog ChangelogSettings `json:"changelog"`
+	Version   VersionSettings   `json:"version"`
+	// VersionFiles are rewritten with the new version and committed before
+	// tagging.
+	VersionFiles []VersionFile `json:"version_files"`
+	// Components split a monorepo into packages released on their own.
+	Components []Component `json:"components"`
+	// Prompts answers the prompts in non-interactive mode, keyed by
+	// decision; "default" takes the answer of an empty reply.
+	Prompts map[string]string `json:"prompts"`
+}
+
+// Prompted decisions.
+const (
+	PromptReleaseType     = "release_type"
+	PromptConfirmRelease  = "confirm_release"
+	PromptConfirmRollback = "confirm_rollback"
+)
+
+type PHPSettings struct {
+	// NamedArguments treats parameter renames as breaking, since callers
+	// using named arguments would stop compiling.
+	NamedArguments bool `json:"named_arguments"`
+}
+
+// Commit signal precedence values.
+const (
+	PrecedenceMax     = "max"
+	PrecedenceCommits = "commits"
+	PrecedenceDiff    = "diff"
+)
+
+type CommitSettings struct {
+	// Precedence decides how Conventional Commits signals combine with the
+	// diff heuristics: "max" (default) takes the higher of both, "commits"
+	// lets the commits decide whenever any follow the convention, and "diff"
+	// only consults the commits when the diff found no major or minor change.
+	Precedence string `json:"precedence"`
+	// Types lists commit types accepted beyond feat, fix, perf, refactor,
+	// docs, test, build, ci, chore, style and revert. Their commits are
+	// listed under Other Changes.
+	Types []string `json:"types"`
+}
+
+type NotesSettings struct {
+	// Sections groups pull requests by label, in order. A pull request is
+	// listed under the first section sharing one of its labels.
+	Sections []NotesSection `json:"sections"`
+	// ExcludeLabels leaves pull requests carrying any of these labels out
+	// of the release notes.
+	ExcludeLabels []string `json:"exclude_labels"`
+	// Template is a text/template file, relative to the repository root,
+	// that replaces the built-in release notes layout.
+	Template string `json:"template"`
+}
+
+type NotesSection struct {
+	Title  string   `json:"title"`
+	Labels []string `json:"labels"`
+}
+
+type ChangelogSettings struct {
+	// Path is the Keep a Changelog file, relative to the repository root.
+	// When set, the file is created if missing; otherwise CHANGELOG.md is
+	// only updated when it exists.
+	Path string `json:"path"`
+}
+
+// Versioning schemes.
+const (
+	SchemeSemVer = "semver"
+	SchemeCalVer = "calver"
+)
+
+// Current version sources.
+const (
+	SourceTags   = "tags"
+	SourceGitHub = "github"
+)
+
+type VersionSettings struct {
+	// Scheme is "semver" (default) or "calver".
+	Scheme string `json:"scheme"`
+	// Format is the CalVer layout, such as "YYYY.0M.MICRO" or "YY.MM.DD".
+	Format string `json:"format"`
+	// Source is where the current version is read from: "tags" (default)
+	// picks the highest matching tag reachable from HEAD, "github" the
+	// latest GitHub release.
+	Source string `json:"source"`
+	// TagPrefix is the part of the tag before the version, such as "v" or
+	// "api/v". When empty, tags with and without a "v" prefix match.
+	TagPrefix string `json:"tag_prefix"`
+}
+
+// Version file types.
+const (
+	VersionFileJSON     = "json"
+	VersionFilePHPConst = "php-const"
+	VersionFilePHPArray = "php-array"
+	VersionFilePlain    = "plain"
+	VersionFileRegex    = "regex"
+)
+
+type VersionFile struct {
+	Path string `json:"path"`
+	// Type selects how the version is located. It is inferred from the path
+	// when empty: *.json files are json, config/*.php files are php-array,
+	// other PHP files are php-const and anything else is plain.
+	Type string `json:"type"`
+	// Key is the top-level JSON key, the PHP constant or the config array
+	// key holding the version. It defaults to "version", or "VERSION" for
+	// constants.
+	Key string `json:"key"`
+	// Pattern is the regular expression of regex targets; its first
+	// capture group is replaced with the version.
+	Pattern string `json:"pattern"`
+}
+
+// Component is a separately released package of a monorepo.
+type Component struct {
+	Name string `json:"name"`
+	// Path is the component directory, relative to the repository root.
+	// Only changes below it are considered.
+	Path string `json:"path"`
+	// TagPrefix defaults to the name followed by "-v", as in billing-v1.4.0.
+	TagPrefix string          `json:"tag_prefix"`
+	Version   VersionSettings `json:"version"`
+	// Analyzers restricts release type detection to the named analyzers.
+	// All analyzers run when empty.
+	Analyzers []string `json:"analyzers"`
+	// VersionFiles and Changelog paths are relative to the component path.
+	VersionFiles []VersionFile     `json:"version_files"`
+	Changelog    ChangelogSettings `json:"changelog"`
+}
@@ -0,0 +1,174 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"releaser/tool/shared"
+)
+
+const defaultCalVerFormat = "YYYY.0M.MICRO"
+
+// calverCounters are the format segments incremented between releases, from
+// the most to the least significant.
+var calverCounters = []string{"MAJOR", "MINOR", "MICRO"}
+
+// calverScheme implements calendar versioning (https://calver.org). Date
+// segments come from the release date; counters are reset when the date
+// segments change and otherwise incremented according to the release type.
+type calverScheme struct {
+	format   string
+	segments []string
+}
+
+func newCalVer(format string) (calverScheme, error) {
+	if format == "" {
+		format = defaultCalVerFormat
+	}
+	c := calverScheme{format: format, segments: strings.Split(format, ".")}
+	for _, segment := range c.segments {
+		if _, ok := calverDateSegment(segment, time.Time{}); ok || isCalVerCounter(segment) {
+			continue
+		}
+		return calverScheme{}, fmt.Errorf("invalid calver format %q: unknown segment %q", format, segment)
+	}
+	return c, nil
+}
+
+func (calverScheme) Name() string {
+	return shared.SchemeCalVer
+}
+
+func (calverScheme) Types() []string {
+	return []string{"major", "minor", "patch"}
+}
+
+func (c calverScheme) Next(current, releaseType, preid string, now time.Time) (string, error) {
+	switch releaseType {
+	case "major", "minor", "patch":
+	default:
+		return "", fmt.Errorf("release type %s is not supported by the calver scheme", releaseType)
+	}
+
+	previous := strings.Split(current, ".")
+	sameDate := len(previous) == len(c.segments)
+	for i, segment := range c.segments {
+		if date, ok := calverDateSegment(segment, now); ok && (!sameDate || strings.TrimLeft(previous[i], "0") != strings.TrimLeft(date, "0")) {
+			sameDate = false
+		}
+	}
+
+	bumped := c.bumpedCounter(releaseType)
+	if sameDate && bumped < 0 {
+		return "", errors.New("version " + current + " was already released for this date; add MICRO to the calver format " + c.format)
+	}
+
+	next := make([]string, len(c.segments))
+	reset := false
+	for i, segment := range c.segments {
+		if date, ok := calverDateSegment(segment, now); ok {
+			next[i] = date
+			continue
+		}
+		counter := 0
+		if len(previous) == len(c.segments) {
+			counter, _ = strconv.Atoi(previous[i])
+		}
+		switch {
+		case reset:
+			counter = 0
+		case i == bumped && (sameDate || segment == "MAJOR"):
+			counter++
+			reset = true
+		case !sameDate && segment != "MAJOR":
+			counter = 0
+		}
+		next[i] = strconv.Itoa(counter)
+	}
+	return strings.Join(next, "."), nil
+}
+
+func (calverScheme) IsPrerelease(string) bool {
+	return false
+}
+
+func (c calverScheme) Valid(version string) bool {
+	parts := strings.Split(version, ".")
+	if len(parts) != len(c.segments) {
+		return false
+	}
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (calverScheme) Compare(a, b string) int {
+	x, y := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(x) && i < len(y); i++ {
+		m, _ := strconv.Atoi(x[i])
+		n, _ := strconv.Atoi(y[i])
+		if c := compareInts(m, n); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(x), len(y))
+}
+
+// bumpedCounter returns the index of the counter segment the release type
+// increments: MAJOR, MINOR or MICRO, falling back to the least significant
+// counter of the format. It is -1 when the format has no counter.
+func (c calverScheme) bumpedCounter(releaseType string) int {
+	wanted := map[string]string{"major": "MAJOR", "minor": "MINOR", "patch": "MICRO"}[releaseType]
+	last := -1
+	for i, segment := range c.segments {
+		if segment == wanted {
+			return i
+		}
+		if isCalVerCounter(segment) {
+			last = i
+		}
+	}
+	return last
+}
+
+func isCalVerCounter(segment string) bool {
+	for _, counter := range calverCounters {
+		if segment == counter {
+			return true
+		}
+	}
+	return false
+}
+
+// calverDateSegment renders a date segment of the format for t.
+func calverDateSegment(segment string, t time.Time) (string, bool) {
+	_, week := t.ISOWeek()
+	switch segment {
+	case "YYYY":
+		return strconv.Itoa(t.Year()), true
+	case "YY":
+		return strconv.Itoa(t.Year() - 2000), true
+	case "0Y":
+		return fmt.Sprintf("%02d", t.Year()-2000), true
+	case "MM":
+		return strconv.Itoa(int(t.Month())), true
+	case "0M":
+		return fmt.Sprintf("%02d", int(t.Month())), true
+	case "WW":
+		return strconv.Itoa(week), true
+	case "0W":
+		return fmt.Sprintf("%02d", week), true
+	case "DD":
+		return strconv.Itoa(t.Day()), true
+	case "0D":
+		return fmt.Sprintf("%02d", t.Day()), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,52 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"releaser/tool/shared"
+)
+
+func TestCalVerNext(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+	cases := []struct {
+		format, current, kind, expected string
+	}{
+		{"YYYY.0M.MICRO", "2026.03.4", "patch", "2026.03.5"},
+		{"YYYY.0M.MICRO", "2026.02.4", "patch", "2026.03.0"},
+		{"YYYY.0M.MICRO", "v1.2.3", "minor", "2026.03.0"},
+		{"YYYY.MM.MICRO", "2026.3.1", "major", "2026.3.2"},
+		{"YY.MM.DD", "26.2.27", "patch", "26.3.2"},
+		{"0Y.0M.0D", "25.12.31", "patch", "26.03.02"},
+		{"MAJOR.YYYY.MICRO", "3.2025.7", "patch", "3.2026.0"},
+		{"MAJOR.YYYY.MICRO", "3.2026.7", "major", "4.2026.0"},
+		{"YYYY.MINOR.MICRO", "2026.1.4", "minor", "2026.2.0"},
+	}
+	for _, c := range cases {
+		scheme, err := SchemeFor(shared.VersionSettings{Scheme: "calver", Format: c.format})
+		if err != nil {
+			t.Fatal(err)
+		}
+		next, err := scheme.Next(c.current, c.kind, "", now)
+		if err != nil {
+			t.Fatalf("%s from %s: %v", c.format, c.current, err)
+		}
+		if next != c.expected {
+			t.Fatalf("%s from %s (%s): expected %s, got %s", c.format, c.current, c.kind, c.expected, next)
+		}
+	}
+}
+
+func TestCalVerNext_Errors(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+	scheme, _ := SchemeFor(shared.VersionSettings{Scheme: "calver", Format: "YY.MM.DD"})
+	if _, err := scheme.Next("26.3.2", "patch", "", now); err == nil {
+		t.Fatalf("expected a second release on the same day to fail without a counter")
+	}
+	if _, err := scheme.Next("26.3.1", "prerelease", "rc", now); err == nil {
+		t.Fatalf("expected pre-release types to be rejected")
+	}
+	if _, err := SchemeFor(shared.VersionSettings{Scheme: "calver", Format: "YYYY.QQ"}); err == nil {
+		t.Fatalf("expected an unknown segment to be rejected")
+	}
+}
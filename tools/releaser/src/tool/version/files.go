@@ -0,0 +1,237 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"releaser/tool/output"
+	"releaser/tool/shared"
+)
+
+var errVersionNotFound = errors.New("version not found")
+
+// UpdateFiles writes the new version into the version files configured in
+// .releaser.json. Nothing is written unless every target is found.
+func UpdateFiles(cfg *shared.Config) error {
+	targets := cfg.Project.VersionFiles
+	if len(targets) == 0 {
+		output.Verbose("No version files configured; skipping version file update")
+		return nil
+	}
+	output.Info("Updating version files to " + cfg.NewVer + "...")
+
+	updated := make(map[string]string)
+	var failed []string
+	for _, target := range targets {
+		file := filepath.Join(cfg.BaseDir, cfg.RepoPath(target.Path))
+		src, ok := updated[file]
+		if !ok {
+			b, err := os.ReadFile(file)
+			if err != nil {
+				output.Warn("Failed to read version file " + target.Path + ": " + err.Error())
+				failed = append(failed, target.Path)
+				continue
+			}
+			src = string(b)
+		}
+		next, err := setVersion(target, src, cfg.NewVer)
+		if err != nil {
+			output.Warn("Failed to update " + target.Path + ": " + err.Error())
+			failed = append(failed, target.Path)
+			continue
+		}
+		updated[file] = next
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("Version could not be updated in %s", strings.Join(failed, ", "))
+	}
+
+	for _, target := range targets {
+		file := filepath.Join(cfg.BaseDir, cfg.RepoPath(target.Path))
+		content, pending := updated[file]
+		if !pending {
+			continue
+		}
+		delete(updated, file)
+		if err := cfg.Plan.WriteFile(cfg.BaseDir, cfg.RepoPath(target.Path), []byte(content)); err != nil {
+			return err
+		}
+		output.Verbose("Set version " + cfg.NewVer + " in " + target.Path)
+		cfg.ReleaseFiles = append(cfg.ReleaseFiles, cfg.RepoPath(target.Path))
+	}
+	return nil
+}
+
+// setVersion replaces the version held by target in src.
+func setVersion(target shared.VersionFile, src, version string) (string, error) {
+	switch versionFileType(target) {
+	case shared.VersionFileJSON:
+		start, end, ok := JSONStringRange(src, versionKey(target, "version"))
+		if !ok {
+			return "", errVersionNotFound
+		}
+		return src[:start] + version + src[end:], nil
+	case shared.VersionFilePHPConst:
+		pattern := regexp.MustCompile(`const\s+` + regexp.QuoteMeta(versionKey(target, "VERSION")) + `\s*=\s*['"]([^'"]*)['"]`)
+		return replaceVersion(pattern, src, version)
+	case shared.VersionFilePHPArray:
+		pattern := regexp.MustCompile(`['"]` + regexp.QuoteMeta(versionKey(target, "version")) + `['"]\s*=>\s*(?:env\(\s*['"][^'"]*['"]\s*,\s*)?['"]([^'"]*)['"]`)
+		return replaceVersion(pattern, src, version)
+	case shared.VersionFileRegex:
+		pattern, err := regexp.Compile(target.Pattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid pattern: %w", err)
+		}
+		if pattern.NumSubexp() == 0 {
+			return "", errors.New("pattern has no capture group")
+		}
+		return replaceVersion(pattern, src, version)
+	default:
+		trimmed := strings.TrimSpace(src)
+		if trimmed == "" {
+			return version + "\n", nil
+		}
+		start := strings.Index(src, trimmed)
+		return src[:start] + version + src[start+len(trimmed):], nil
+	}
+}
+
+func versionFileType(target shared.VersionFile) string {
+	if target.Type != "" {
+		return target.Type
+	}
+	file := filepath.ToSlash(target.Path)
+	switch {
+	case strings.HasSuffix(file, ".json"):
+		return shared.VersionFileJSON
+	case strings.HasSuffix(file, ".php") && path.Dir(file) == "config":
+		return shared.VersionFilePHPArray
+	case strings.HasSuffix(file, ".php"):
+		return shared.VersionFilePHPConst
+	default:
+		return shared.VersionFilePlain
+	}
+}
+
+func versionKey(target shared.VersionFile, fallback string) string {
+	if target.Key != "" {
+		return target.Key
+	}
+	return fallback
+}
+
+// replaceVersion replaces the first capture group of every match.
+func replaceVersion(pattern *regexp.Regexp, src, version string) (string, error) {
+	matches := pattern.FindAllStringSubmatchIndex(src, -1)
+	var out strings.Builder
+	last, found := 0, false
+	for _, m := range matches {
+		if m[2] < 0 {
+			continue
+		}
+		out.WriteString(src[last:m[2]])
+		out.WriteString(version)
+		last, found = m[3], true
+	}
+	if !found {
+		return "", errVersionNotFound
+	}
+	out.WriteString(src[last:])
+	return out.String(), nil
+}
+
+// JSONStringRange returns the byte range of the string value at the key
+// path, such as ("require", "acme/billing"), leaving the rest of the document
+// and its formatting untouched.
+func JSONStringRange(src string, keys ...string) (int, int, bool) {
+	start := skipJSONSpace(src, 0)
+	for i, key := range keys {
+		if start >= len(src) || src[start] != '{' {
+			return 0, 0, false
+		}
+		value, ok := jsonMemberValue(src, start, key)
+		if !ok {
+			return 0, 0, false
+		}
+		if i < len(keys)-1 {
+			start = value
+			continue
+		}
+		if src[value] != '"' {
+			return 0, 0, false
+		}
+		end := jsonStringEnd(src, value)
+		if end < 0 {
+			return 0, 0, false
+		}
+		return value + 1, end, true
+	}
+	return 0, 0, false
+}
+
+// jsonMemberValue returns the index where the value of key starts in the
+// object opening at start.
+func jsonMemberValue(src string, start int, key string) (int, bool) {
+	depth := 0
+	expectKey := false
+	for i := start; i < len(src); i++ {
+		switch src[i] {
+		case '{':
+			depth++
+			expectKey = depth == 1
+		case '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return 0, false
+			}
+		case ',':
+			expectKey = depth == 1
+		case '"':
+			end := jsonStringEnd(src, i)
+			if end < 0 {
+				return 0, false
+			}
+			if depth == 1 && expectKey {
+				expectKey = false
+				if src[i+1:end] == key {
+					j := skipJSONSpace(src, end+1)
+					if j >= len(src) || src[j] != ':' {
+						return 0, false
+					}
+					j = skipJSONSpace(src, j+1)
+					return j, j < len(src)
+				}
+			}
+			i = end
+		}
+	}
+	return 0, false
+}
+
+// jsonStringEnd returns the index of the quote closing the string starting
+// at start.
+func jsonStringEnd(src string, start int) int {
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func skipJSONSpace(src string, i int) int {
+	for i < len(src) && strings.ContainsRune(" \t\r\n", rune(src[i])) {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,95 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"releaser/tool/shared"
+)
+
+func TestSetVersion(t *testing.T) {
+	cases := []struct {
+		name     string
+		target   shared.VersionFile
+		src      string
+		expected string
+	}{
+		{
+			name:     "composer.json",
+			target:   shared.VersionFile{Path: "composer.json"},
+			src:      "{\n    \"name\": \"acme/app\",\n    \"extra\": {\"version\": \"0.0.1\"},\n    \"version\": \"1.1.0\"\n}\n",
+			expected: "{\n    \"name\": \"acme/app\",\n    \"extra\": {\"version\": \"0.0.1\"},\n    \"version\": \"1.2.0\"\n}\n",
+		},
+		{
+			name:     "php constant",
+			target:   shared.VersionFile{Path: "src/Acme.php"},
+			src:      "<?php\nclass Acme\n{\n    public const VERSION = '1.1.0';\n}\n",
+			expected: "<?php\nclass Acme\n{\n    public const VERSION = '1.2.0';\n}\n",
+		},
+		{
+			name:     "laravel config",
+			target:   shared.VersionFile{Path: "config/app.php"},
+			src:      "<?php\nreturn [\n    'name' => env('APP_NAME', 'Acme'),\n    'version' => env('APP_VERSION', '1.1.0'),\n];\n",
+			expected: "<?php\nreturn [\n    'name' => env('APP_NAME', 'Acme'),\n    'version' => env('APP_VERSION', '1.2.0'),\n];\n",
+		},
+		{
+			name:     "plain file",
+			target:   shared.VersionFile{Path: "VERSION"},
+			src:      "1.1.0\n",
+			expected: "1.2.0\n",
+		},
+		{
+			name:     "regex",
+			target:   shared.VersionFile{Path: "README.md", Type: "regex", Pattern: `acme/app:v([0-9.]+)`},
+			src:      "docker pull acme/app:v1.1.0\ndocker run acme/app:v1.1.0\n",
+			expected: "docker pull acme/app:v1.2.0\ndocker run acme/app:v1.2.0\n",
+		},
+	}
+	for _, c := range cases {
+		got, err := setVersion(c.target, c.src, "1.2.0")
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got != c.expected {
+			t.Fatalf("%s: unexpected content:\n%s", c.name, got)
+		}
+	}
+}
+
+func TestUpdateFiles_RefusesMissingTargets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"name": "acme"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "VERSION"), []byte("1.1.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &shared.Config{
+		BaseDir: dir,
+		NewVer:  "1.2.0",
+		Project: shared.Project{VersionFiles: []shared.VersionFile{{Path: "VERSION"}, {Path: "package.json"}}},
+	}
+
+	if err := UpdateFiles(cfg); err == nil {
+		t.Fatalf("expected an error for package.json without a version")
+	}
+	b, _ := os.ReadFile(filepath.Join(dir, "VERSION"))
+	if string(b) != "1.1.0\n" {
+		t.Fatalf("expected no file to be written, got %q", b)
+	}
+	if len(cfg.ReleaseFiles) != 0 {
+		t.Fatalf("unexpected release files %v", cfg.ReleaseFiles)
+	}
+}
+
+func TestJSONStringRange_NestedKey(t *testing.T) {
+	src := `{"name": "acme/app", "require-dev": {"acme/billing": "^0.9"}, "require": {"php": "^8.2", "acme/billing": "^1.4"}}`
+	start, end, ok := JSONStringRange(src, "require", "acme/billing")
+	if !ok || src[start:end] != "^1.4" {
+		t.Fatalf("unexpected range %d..%d (%v)", start, end, ok)
+	}
+	if _, _, ok := JSONStringRange(src, "require", "acme/search"); ok {
+		t.Fatalf("expected a missing key not to be found")
+	}
+}
@@ -0,0 +1,93 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"releaser/tool/shared"
+)
+
+// Scheme computes the next version from the current one.
+type Scheme interface {
+	Name() string
+	// Types lists the release types Next supports.
+	Types() []string
+	// Next returns the version following current for the release type.
+	Next(current, releaseType, preid string, now time.Time) (string, error)
+	// IsPrerelease reports whether the GitHub release should be marked as
+	// a pre-release.
+	IsPrerelease(version string) bool
+	// Valid reports whether version follows the scheme.
+	Valid(version string) bool
+	// Compare orders two valid versions, returning -1, 0 or 1.
+	Compare(a, b string) int
+}
+
+// SchemeFor returns the versioning scheme configured in .releaser.json.
+func SchemeFor(settings shared.VersionSettings) (Scheme, error) {
+	switch settings.Scheme {
+	case "", shared.SchemeSemVer:
+		return semverScheme{}, nil
+	case shared.SchemeCalVer:
+		return newCalVer(settings.Format)
+	default:
+		return nil, fmt.Errorf("unknown version scheme %q (expected semver or calver)", settings.Scheme)
+	}
+}
+
+// CheckType rejects a release type the configured scheme does not support.
+func CheckType(cfg *shared.Config) error {
+	scheme, err := SchemeFor(cfg.Project.Version)
+	if err != nil {
+		return err
+	}
+	return checkType(scheme, cfg.Type)
+}
+
+func checkType(scheme Scheme, releaseType string) error {
+	for _, t := range scheme.Types() {
+		if t == releaseType {
+			return nil
+		}
+	}
+	return fmt.Errorf("Release type %s is not supported by the %s scheme (expected %s)", releaseType, scheme.Name(), strings.Join(scheme.Types(), ", "))
+}
+
+type semverScheme struct{}
+
+func (semverScheme) Name() string {
+	return shared.SchemeSemVer
+}
+
+func (semverScheme) Types() []string {
+	return []string{"major", "minor", "patch", PreMajor, PreMinor, PrePatch, Prerelease, Release}
+}
+
+func (semverScheme) Next(current, releaseType, preid string, _ time.Time) (string, error) {
+	v, err := Parse(current)
+	if err != nil {
+		return "", err
+	}
+	next, err := v.Bump(releaseType, preid)
+	if err != nil {
+		return "", err
+	}
+	return next.String(), nil
+}
+
+func (semverScheme) IsPrerelease(version string) bool {
+	v, err := Parse(version)
+	return err == nil && v.IsPrerelease()
+}
+
+func (semverScheme) Valid(version string) bool {
+	_, err := Parse(version)
+	return err == nil
+}
+
+func (semverScheme) Compare(a, b string) int {
+	x, _ := Parse(a)
+	y, _ := Parse(b)
+	return x.Compare(y)
+}
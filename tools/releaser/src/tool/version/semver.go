@@ -0,0 +1,203 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SemVer is a Semantic Versioning 2.0.0 version.
+type SemVer struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+	Build      []string
+}
+
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+var identifierPattern = regexp.MustCompile(`^[0-9A-Za-z-]+$`)
+
+// Bump kinds on top of major, minor and patch.
+const (
+	PreMajor   = "premajor"
+	PreMinor   = "preminor"
+	PrePatch   = "prepatch"
+	Prerelease = "prerelease"
+	Release    = "release"
+)
+
+// Parse reads a version such as 1.2.3, v2.0.0-rc.1 or 1.0.0+build.5.
+func Parse(s string) (SemVer, error) {
+	m := semverPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return SemVer{}, fmt.Errorf("invalid semantic version %q", s)
+	}
+	v := SemVer{}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	if m[4] != "" {
+		v.Prerelease = strings.Split(m[4], ".")
+		for _, id := range v.Prerelease {
+			if len(id) > 1 && id[0] == '0' && isNumeric(id) {
+				return SemVer{}, fmt.Errorf("invalid semantic version %q: numeric identifier %s has a leading zero", s, id)
+			}
+		}
+	}
+	if m[5] != "" {
+		v.Build = strings.Split(m[5], ".")
+	}
+	return v, nil
+}
+
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// IsPrerelease reports whether the version has pre-release identifiers.
+func (v SemVer) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare returns -1, 0 or 1 following SemVer precedence; build metadata is
+// ignored.
+func (v SemVer) Compare(o SemVer) int {
+	for _, pair := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if c := compareInts(pair[0], pair[1]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := compareIdentifiers(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(v.Prerelease), len(o.Prerelease))
+}
+
+// Bump returns the next version for kind. preid names the pre-release
+// channel (for example "rc") of the pre* kinds. Bumping a pre-release with
+// major, minor or patch releases it when it already targets that version,
+// so 2.0.0-rc.1 bumps to 2.0.0 with major.
+func (v SemVer) Bump(kind, preid string) (SemVer, error) {
+	if preid != "" && !identifierPattern.MatchString(preid) {
+		return SemVer{}, fmt.Errorf("invalid pre-release identifier %q", preid)
+	}
+	next := SemVer{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	switch kind {
+	case "major":
+		if !v.IsPrerelease() || v.Minor != 0 || v.Patch != 0 {
+			next = SemVer{Major: v.Major + 1}
+		}
+	case "minor":
+		if !v.IsPrerelease() || v.Patch != 0 {
+			next = SemVer{Major: v.Major, Minor: v.Minor + 1}
+		}
+	case "patch":
+		if !v.IsPrerelease() {
+			next.Patch++
+		}
+	case PreMajor:
+		next = SemVer{Major: v.Major + 1, Prerelease: firstPrerelease(preid)}
+	case PreMinor:
+		next = SemVer{Major: v.Major, Minor: v.Minor + 1, Prerelease: firstPrerelease(preid)}
+	case PrePatch:
+		next.Patch++
+		next.Prerelease = firstPrerelease(preid)
+	case Prerelease:
+		switch {
+		case !v.IsPrerelease():
+			next.Patch++
+			next.Prerelease = firstPrerelease(preid)
+		case preid != "" && v.Prerelease[0] != preid:
+			next.Prerelease = firstPrerelease(preid)
+		default:
+			next.Prerelease = incrementPrerelease(v.Prerelease)
+		}
+	case Release:
+		if !v.IsPrerelease() {
+			return SemVer{}, fmt.Errorf("%s is not a pre-release", v)
+		}
+	default:
+		return SemVer{}, fmt.Errorf("Invalid release type: %s", kind)
+	}
+	return next, nil
+}
+
+func firstPrerelease(preid string) []string {
+	if preid == "" {
+		return []string{"0"}
+	}
+	return []string{preid, "0"}
+}
+
+// incrementPrerelease bumps the last numeric identifier, appending .0 when
+// there is none.
+func incrementPrerelease(ids []string) []string {
+	next := append([]string(nil), ids...)
+	for i := len(next) - 1; i >= 0; i-- {
+		if isNumeric(next[i]) {
+			n, _ := strconv.Atoi(next[i])
+			next[i] = strconv.Itoa(n + 1)
+			return next
+		}
+	}
+	return append(next, "0")
+}
+
+func compareIdentifiers(a, b string) int {
+	aNum, bNum := isNumeric(a), isNumeric(b)
+	switch {
+	case aNum && bNum:
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return compareInts(x, y)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
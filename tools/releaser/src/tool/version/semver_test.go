@@ -0,0 +1,81 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	v, err := Parse("v2.0.0-beta.3+build.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Major != 2 || v.Minor != 0 || v.Patch != 0 || v.String() != "2.0.0-beta.3+build.7" {
+		t.Fatalf("unexpected version %+v", v)
+	}
+	for _, invalid := range []string{"1.2", "1.2.3-", "01.2.3", "1.2.3-01", "1.2.3-beta..1"} {
+		if _, err := Parse(invalid); err == nil {
+			t.Fatalf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestCompare_FollowsPrecedence(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+	for i := 1; i < len(ordered); i++ {
+		a, _ := Parse(ordered[i-1])
+		b, _ := Parse(ordered[i])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Fatalf("expected %s < %s", a, b)
+		}
+	}
+	a, _ := Parse("1.0.0+build.1")
+	b, _ := Parse("1.0.0+build.2")
+	if a.Compare(b) != 0 {
+		t.Fatalf("expected build metadata to be ignored")
+	}
+}
+
+func TestBump(t *testing.T) {
+	cases := []struct {
+		from, kind, preid, expected string
+	}{
+		{"1.2.3", "major", "", "2.0.0"},
+		{"1.2.3", "minor", "", "1.3.0"},
+		{"1.2.3+build.1", "patch", "", "1.2.4"},
+		{"2.0.0-beta.3", "major", "", "2.0.0"},
+		{"2.0.0-beta.3", "patch", "", "2.0.0"},
+		{"1.2.0-rc.1", "minor", "", "1.2.0"},
+		{"1.2.3-rc.1", "minor", "", "1.3.0"},
+		{"1.2.3", "premajor", "rc", "2.0.0-rc.0"},
+		{"1.2.3", "preminor", "", "1.3.0-0"},
+		{"1.2.3", "prepatch", "beta", "1.2.4-beta.0"},
+		{"1.2.3", "prerelease", "rc", "1.2.4-rc.0"},
+		{"2.0.0-rc.0", "prerelease", "", "2.0.0-rc.1"},
+		{"2.0.0-rc.0", "prerelease", "rc", "2.0.0-rc.1"},
+		{"2.0.0-beta.3", "prerelease", "rc", "2.0.0-rc.0"},
+		{"2.0.0-beta", "prerelease", "", "2.0.0-beta.0"},
+		{"2.0.0-rc.1", "release", "", "2.0.0"},
+	}
+	for _, c := range cases {
+		v, err := Parse(c.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		next, err := v.Bump(c.kind, c.preid)
+		if err != nil {
+			t.Fatalf("%s %s: %v", c.from, c.kind, err)
+		}
+		if next.String() != c.expected {
+			t.Fatalf("%s %s --preid %q: expected %s, got %s", c.from, c.kind, c.preid, c.expected, next)
+		}
+	}
+
+	v, _ := Parse("1.2.3")
+	if _, err := v.Bump("release", ""); err == nil {
+		t.Fatalf("expected release of a stable version to fail")
+	}
+	if _, err := v.Bump("prerelease", "rc.1"); err == nil {
+		t.Fatalf("expected an invalid preid to be rejected")
+	}
+}
@@ -0,0 +1,172 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+
+	"releaser/tool/githubapi"
+	"releaser/tool/gitops"
+	"releaser/tool/output"
+	"releaser/tool/shared"
+)
+
+// releaseTag is a tag split into its prefix and version.
+type releaseTag struct {
+	name    string
+	prefix  string
+	version string
+}
+
+// GetCurrentVersion reads the previous release from the configured source,
+// git tags by default, and warns when the other source disagrees.
+func GetCurrentVersion(cfg *shared.Config) error {
+	settings := cfg.Project.Version
+	scheme, err := SchemeFor(settings)
+	if err != nil {
+		return err
+	}
+
+	if settings.Source == shared.SourceGitHub {
+		if settings.TagPrefix == "" {
+			if err := githubapi.GetCurrentVersion(cfg); err != nil {
+				return err
+			}
+		} else if err := latestPrefixedRelease(cfg, settings.TagPrefix, scheme); err != nil {
+			return err
+		}
+		if tag, found, _, err := latestTag(cfg.BaseDir, settings.TagPrefix, scheme); err != nil {
+			output.Verbose("Could not compare with local tags: " + err.Error())
+		} else if found && tag.name != cfg.OldTag {
+			output.Warn("Latest GitHub release is " + cfg.OldTag + " but the highest tag reachable from HEAD is " + tag.name)
+		}
+		return nil
+	}
+
+	label := "Finding latest release tag"
+	output.Info(label + "...")
+	if err := gitops.FetchTags(cfg); err != nil {
+		output.Verbose("Failed to fetch remote tags: " + err.Error())
+	}
+	tag, found, skipped, err := latestTag(cfg.BaseDir, settings.TagPrefix, scheme)
+	if err != nil {
+		output.ReplaceLastLine(label + " ⚠")
+		output.Warn("Failed to list git tags")
+		return err
+	}
+	if !found {
+		output.ReplaceLastLine(label + " ⚠")
+		if len(skipped) > 0 {
+			return fmt.Errorf("Found %d tag(s) matching %q reachable from HEAD, none valid %s (such as %s)", len(skipped), settings.TagPrefix+"*", schemeLabel(scheme), skipped[0])
+		}
+		return fmt.Errorf("No %s tag matching %q is reachable from HEAD", scheme.Name(), settings.TagPrefix+"*")
+	}
+	cfg.OldTag = tag.name
+	cfg.TagPrefix = tag.prefix
+	cfg.OldVer = tag.version
+	output.ReplaceLastLine(label + ": " + cfg.OldTag + " ✔")
+
+	if cfg.Token == "" {
+		return nil
+	}
+	if latest, err := latestGitHubTag(cfg, settings.TagPrefix, scheme); err != nil {
+		output.Verbose("Could not compare with the latest GitHub release: " + err.Error())
+	} else if latest != "" && latest != tag.name {
+		output.Warn("Highest tag reachable from HEAD is " + tag.name + " but the latest GitHub release is " + latest)
+	}
+	return nil
+}
+
+// releaseTags lists the published GitHub release tags; tests replace it.
+var releaseTags = githubapi.ReleaseTags
+
+// latestPrefixedRelease reads the previous release from the GitHub releases
+// whose tag starts with prefix. /releases/latest is repository-wide, so a
+// component sharing the repository would pick up another one's release.
+func latestPrefixedRelease(cfg *shared.Config, prefix string, scheme Scheme) error {
+	label := "Fetching latest GitHub release"
+	output.Info(label + " matching " + prefix + "*...")
+	tags, err := releaseTags(cfg)
+	if err != nil {
+		output.ReplaceLastLine(label + " ⚠")
+		output.Warn("Failed to list GitHub releases")
+		return err
+	}
+	tag, found, _ := highestTag(tags, prefix, scheme)
+	if !found {
+		output.ReplaceLastLine(label + " ⚠")
+		return fmt.Errorf("No GitHub release with a %s tag matching %q", scheme.Name(), prefix+"*")
+	}
+	cfg.OldTag = tag.name
+	cfg.TagPrefix = tag.prefix
+	cfg.OldVer = tag.version
+	output.ReplaceLastLine(label + ": " + cfg.OldTag + " ✔")
+	return nil
+}
+
+// latestGitHubTag returns the tag of the latest GitHub release matching
+// prefix, or an empty string when there is none.
+func latestGitHubTag(cfg *shared.Config, prefix string, scheme Scheme) (string, error) {
+	if prefix == "" {
+		return githubapi.LatestReleaseTag(cfg)
+	}
+	tags, err := releaseTags(cfg)
+	if err != nil {
+		return "", err
+	}
+	tag, _, _ := highestTag(tags, prefix, scheme)
+	return tag.name, nil
+}
+
+// latestTag returns the highest tag reachable from HEAD, along with the
+// matching tags the scheme rejected.
+func latestTag(dir, prefix string, scheme Scheme) (releaseTag, bool, []string, error) {
+	tags, err := gitops.MergedTags(dir, prefix)
+	if err != nil {
+		return releaseTag{}, false, nil, err
+	}
+	output.VeryVerboseList("Tags reachable from HEAD", tags, 20)
+	tag, found, skipped := highestTag(tags, prefix, scheme)
+	output.VerboseList("Tags skipped as not valid "+schemeLabel(scheme), skipped, 10)
+	return tag, found, skipped, nil
+}
+
+// highestTag picks the tag with the highest version among those matching
+// prefix. Without a prefix, tags with and without a "v" prefix match. It
+// also returns the matching tags the scheme rejected.
+func highestTag(tags []string, prefix string, scheme Scheme) (releaseTag, bool, []string) {
+	var best releaseTag
+	var skipped []string
+	found := false
+	for _, name := range tags {
+		tag, ok := splitTag(name, prefix)
+		if !ok {
+			continue
+		}
+		if !scheme.Valid(tag.version) {
+			skipped = append(skipped, name)
+			continue
+		}
+		if !found || scheme.Compare(tag.version, best.version) > 0 {
+			best, found = tag, true
+		}
+	}
+	return best, found, skipped
+}
+
+func schemeLabel(scheme Scheme) string {
+	if scheme.Name() == shared.SchemeSemVer {
+		return "SemVer 2.0"
+	}
+	return scheme.Name()
+}
+
+func splitTag(name, prefix string) (releaseTag, bool) {
+	if prefix != "" {
+		if !strings.HasPrefix(name, prefix) {
+			return releaseTag{}, false
+		}
+		return releaseTag{name: name, prefix: prefix, version: strings.TrimPrefix(name, prefix)}, true
+	}
+	version := strings.TrimPrefix(name, "v")
+	return releaseTag{name: name, prefix: strings.TrimSuffix(name, version), version: version}, true
+}
@@ -0,0 +1,72 @@
+package version
+
+import (
+	"testing"
+
+	"releaser/tool/shared"
+)
+
+func TestHighestTag_SortsBySemverPrecedence(t *testing.T) {
+	tags := []string{"v1.9.0", "v1.10.0", "v2.0.0-rc.1", "v2.0.0-beta.3", "api/v3.0.0", "latest", "1.10.1"}
+	tag, found, _ := highestTag(tags, "", semverScheme{})
+	if !found || tag.name != "v2.0.0-rc.1" || tag.prefix != "v" || tag.version != "2.0.0-rc.1" {
+		t.Fatalf("unexpected tag %+v", tag)
+	}
+
+	tag, found, _ = highestTag(tags, "api/v", semverScheme{})
+	if !found || tag.name != "api/v3.0.0" || tag.version != "3.0.0" {
+		t.Fatalf("unexpected component tag %+v", tag)
+	}
+
+	if _, found, skipped := highestTag([]string{"latest"}, "", semverScheme{}); found || len(skipped) != 1 {
+		t.Fatalf("expected no version tag and latest to be skipped, got %v", skipped)
+	}
+}
+
+func TestHighestTag_ReportsTwoPartTags(t *testing.T) {
+	_, found, skipped := highestTag([]string{"v1.1", "v1.2"}, "", semverScheme{})
+	if found {
+		t.Fatalf("expected two-part tags not to be valid SemVer 2.0")
+	}
+	if len(skipped) != 2 || skipped[0] != "v1.1" || skipped[1] != "v1.2" {
+		t.Fatalf("expected both tags to be reported as skipped, got %v", skipped)
+	}
+}
+
+func TestHighestTag_CalVer(t *testing.T) {
+	scheme, err := SchemeFor(shared.VersionSettings{Scheme: "calver", Format: "YYYY.0M.MICRO"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, found, _ := highestTag([]string{"2026.02.9", "2026.03.1", "2026.03.10", "v1.2.3.4"}, "", scheme)
+	if !found || tag.name != "2026.03.10" {
+		t.Fatalf("unexpected tag %+v", tag)
+	}
+}
+
+func TestGetCurrentVersion_GitHubSourceForComponent(t *testing.T) {
+	restore := releaseTags
+	t.Cleanup(func() { releaseTags = restore })
+	releaseTags = func(*shared.Config) ([]string, error) {
+		return []string{"search-v2.0.0", "billing-v1.10.0", "billing-v1.4.0"}, nil
+	}
+
+	cfg := &shared.Config{BaseDir: t.TempDir(), Repo: "acme/app"}
+	cfg = cfg.ForComponent(shared.Component{
+		Name:      "billing",
+		Path:      "packages/billing",
+		TagPrefix: "billing-v",
+		Version:   shared.VersionSettings{Source: shared.SourceGitHub},
+	})
+	if err := GetCurrentVersion(cfg); err != nil {
+		t.Fatalf("GetCurrentVersion returned error: %v", err)
+	}
+	if cfg.OldTag != "billing-v1.10.0" || cfg.TagPrefix != "billing-v" || cfg.OldVer != "1.10.0" {
+		t.Fatalf("unexpected current version %s (prefix %q, version %q)", cfg.OldTag, cfg.TagPrefix, cfg.OldVer)
+	}
+
+	cfg.Project.Version.TagPrefix = "auth-v"
+	if err := GetCurrentVersion(cfg); err == nil {
+		t.Fatalf("expected an error when no release matches the component prefix")
+	}
+}
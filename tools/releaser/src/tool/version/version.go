@@ -3,83 +3,56 @@ package version
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"releaser/tool/output"
 	"releaser/tool/shared"
 )
 
 func Bump(cfg *shared.Config) error {
-	if !cfg.TypeSet {
-		answer := output.Ask(fmt.Sprintf("Please confirm auto-detected release type [major|minor|patch] (detected: %s): ", output.SemverLabel(defaultType(cfg.Type))))
-		if answer != "" {
-			cfg.Type = answer
-		}
-	} else {
-		output.Info("Using provided release type: " + cfg.Type)
+	scheme, err := SchemeFor(cfg.Project.Version)
+	if err != nil {
+		return err
 	}
 
-	major, minor, patch := parse(cfg.OldVer)
-	output.Verbose(fmt.Sprintf("Parsed current version: major=%d minor=%d patch=%d", major, minor, patch))
-	switch cfg.Type {
-	case "major":
-		major++
-		minor = 0
-		patch = 0
-	case "minor":
-		minor++
-		patch = 0
-	case "patch", "":
-		patch++
-		cfg.Type = "patch"
+	switch {
+	case cfg.TypeSet:
+		output.Info("Using provided release type: " + cfg.Type)
+	case cfg.DryRun:
+		output.Info("Using auto-detected release type: " + defaultType(cfg.Type))
 	default:
-		return fmt.Errorf("Invalid release type: %s", cfg.Type)
+		answer, err := output.Prompt(output.Decision{
+			Name:     shared.PromptReleaseType,
+			Question: fmt.Sprintf("Please confirm auto-detected release type [%s] (detected: %s): ", strings.Join(scheme.Types(), "|"), output.SemverLabel(defaultType(cfg.Type))),
+			Default:  defaultType(cfg.Type),
+		})
+		if err != nil {
+			return err
+		}
+		cfg.Type = answer
 	}
 
-	cfg.NewVer = fmt.Sprintf("%d.%d.%d", major, minor, patch)
-	if strings.HasPrefix(cfg.OldTag, "v") {
-		cfg.NewTag = "v" + cfg.NewVer
-	} else {
-		cfg.NewTag = cfg.NewVer
+	if cfg.Type == "" {
+		cfg.Type = "patch"
+	}
+	if err := checkType(scheme, cfg.Type); err != nil {
+		return err
+	}
+	next, err := scheme.Next(cfg.OldVer, cfg.Type, cfg.PreID, time.Now())
+	if err != nil {
+		return fmt.Errorf("Cannot compute the next %s version from %s: %w", scheme.Name(), cfg.OldTag, err)
 	}
+
+	cfg.NewVer = next
+	cfg.Prerelease = scheme.IsPrerelease(next)
+	cfg.NewTag = cfg.TagPrefix + cfg.NewVer
 	output.Info(fmt.Sprintf("Bumping new %s version from %s to %s", cfg.Type, cfg.OldTag, cfg.NewTag))
 	return nil
 }
 
-func DefaultYes(ans string) string {
-	if ans == "" {
-		return "y"
-	}
-	return ans
-}
-
 func defaultType(t string) string {
 	if t == "" {
 		return "patch"
 	}
 	return t
 }
-
-func parse(v string) (int, int, int) {
-	parts := strings.Split(v, ".")
-	major, minor, patch := 0, 0, 0
-	if len(parts) > 0 {
-		major = atoi(parts[0])
-	}
-	if len(parts) > 1 {
-		minor = atoi(parts[1])
-	}
-	if len(parts) > 2 {
-		patch = atoi(parts[2])
-	}
-	return major, minor, patch
-}
-
-func atoi(s string) int {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return 0
-	}
-	var n int
-	_, _ = fmt.Sscanf(s, "%d", &n)
-	return n
-}
@@ -0,0 +1,58 @@
+package version
+
+import (
+	"strings"
+	"testing"
+
+	"releaser/tool/output/outputtest"
+	"releaser/tool/shared"
+)
+
+func TestBump_PromptsForDetectedType(t *testing.T) {
+	cases := []struct {
+		answer string
+		want   string
+	}{
+		{answer: "default", want: "v1.3.0"},
+		{answer: "major", want: "v2.0.0"},
+	}
+	for _, c := range cases {
+		outputtest.Use(t, &outputtest.Script{Answers: map[string]string{shared.PromptReleaseType: c.answer}})
+		cfg := &shared.Config{Type: "minor", OldTag: "v1.2.3", OldVer: "1.2.3", TagPrefix: "v"}
+		if err := Bump(cfg); err != nil {
+			t.Fatalf("answer %q: Bump returned error: %v", c.answer, err)
+		}
+		if cfg.NewTag != c.want {
+			t.Errorf("answer %q: expected %s, got %s", c.answer, c.want, cfg.NewTag)
+		}
+	}
+}
+
+func TestBump_RejectsTypesUnsupportedByScheme(t *testing.T) {
+	calver := shared.Project{Version: shared.VersionSettings{Scheme: shared.SchemeCalVer, Format: "YYYY.0M.MICRO"}}
+	cfg := &shared.Config{Type: "premajor", TypeSet: true, OldTag: "2026.01.0", OldVer: "2026.01.0", Project: calver}
+	err := Bump(cfg)
+	if err == nil || !strings.Contains(err.Error(), "not supported by the calver scheme") {
+		t.Fatalf("expected premajor to be rejected for calver, got %v", err)
+	}
+	if cfg.NewTag != "" {
+		t.Fatalf("expected no new tag, got %s", cfg.NewTag)
+	}
+
+	if err := CheckType(&shared.Config{Type: "minor", Project: calver}); err != nil {
+		t.Fatalf("expected minor to be supported by calver, got %v", err)
+	}
+	if err := CheckType(&shared.Config{Type: "prerelease"}); err != nil {
+		t.Fatalf("expected prerelease to be supported by semver, got %v", err)
+	}
+}
+
+func TestSchemeTypes(t *testing.T) {
+	scheme, err := SchemeFor(shared.VersionSettings{Scheme: shared.SchemeCalVer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(scheme.Types(), "|"); got != "major|minor|patch" {
+		t.Fatalf("unexpected calver types %s", got)
+	}
+}